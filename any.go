@@ -0,0 +1,97 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Encodes a generic value, such as a map[string]interface{} tree from json.Unmarshal, as raw JSON, for handing to code that works with RawObject and RawArray
+//
+// Anything json.Marshal can encode is accepted. Numbers given as json.Number are written exactly as they read, and float64s in the shortest form that reads back the same, so a tree decoded with UseNumber survives the round trip unrounded.
+func FromAny(v interface{}) (json.RawMessage, error) {
+	var (
+		out bytes.Buffer
+		enc = json.NewEncoder(&out)
+	)
+
+	// Strings keep <, >, and & as they are rather than escaping them for HTML
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// Decodes every member into Go's generic JSON types: map[string]interface{}, []interface{}, string, float64, bool, and nil
+//
+// Numbers are decoded as float64, as json.Unmarshal would, unless WithExactNumbers is given, in which case they are json.Number so that none is rounded. Honors WithExactNumbers.
+func (o *RawObject) ToAny(opts ...Option) (map[string]interface{}, error) {
+	var (
+		cfg = newConfig(opts)
+		out = make(map[string]interface{}, len(*o))
+	)
+
+	for k, raw := range *o {
+		v, err := cfg.decodeAny(raw)
+		if err != nil {
+			return nil, fmt.Errorf("value for key %q: %w", k, err)
+		}
+		out[k] = v
+	}
+
+	return out, nil
+}
+
+// Decodes every element into Go's generic JSON types, as RawObject.ToAny does
+//
+// Honors WithExactNumbers.
+func (a *RawArray) ToAny(opts ...Option) ([]interface{}, error) {
+	var (
+		cfg = newConfig(opts)
+		out = make([]interface{}, len(*a))
+	)
+
+	for i, raw := range *a {
+		v, err := cfg.decodeAny(raw)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		out[i] = v
+	}
+
+	return out, nil
+}
+
+// Decodes one raw value into generic types, keeping numbers as json.Number under WithExactNumbers
+func (c *config) decodeAny(raw json.RawMessage) (interface{}, error) {
+	var (
+		v   interface{}
+		dec = json.NewDecoder(bytes.NewReader(raw))
+	)
+
+	if !json.Valid(raw) {
+		return nil, ErrInvalidJson
+	}
+
+	if c.exactNumbers {
+		dec.UseNumber()
+	}
+
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// Describes what a Go value serializes to, for checking structs against sample payloads from the wire
+//
+// The value is encoded with json.Marshal, so struct tags, omitempty, and MarshalJSON methods apply just as they would when sending it, and an error encoding it is returned as is. Honors the options Describe honors.
+func DescribeValue(v interface{}, opts ...Option) (*JsonDescription, error) {
+	raw, err := FromAny(v)
+	if err != nil {
+		return NewJsonDescription(), err
+	}
+
+	return Describe(raw, opts...)
+}
@@ -0,0 +1,73 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Appends each value to the end of the array; every value must be valid JSON, or nothing is appended
+func (a *RawArray) Append(values ...json.RawMessage) error {
+	return a.Insert(len(*a), values...)
+}
+
+// Inserts values before the element at index i, shifting later elements up; i may equal the length to append, and every value must be valid JSON, or nothing is inserted
+func (a *RawArray) Insert(i int, values ...json.RawMessage) error {
+	if i < 0 || i > len(*a) {
+		return fmt.Errorf("array index %d out of range", i)
+	}
+
+	elems, err := validElements(i, values)
+	if err != nil {
+		return err
+	}
+
+	arr := make(RawArray, 0, len(*a)+len(elems))
+	arr = append(arr, (*a)[:i]...)
+	arr = append(arr, elems...)
+	*a = append(arr, (*a)[i:]...)
+
+	return nil
+}
+
+// Removes n elements starting at index i, shifting later elements down, and returns them
+func (a *RawArray) Remove(i, n int) (RawArray, error) {
+	if i < 0 || n < 0 || i+n > len(*a) {
+		return nil, fmt.Errorf("array range [%d:%d] out of range", i, i+n)
+	}
+
+	removed := make(RawArray, n)
+	copy(removed, (*a)[i:i+n])
+
+	*a = append((*a)[:i], (*a)[i+n:]...)
+	return removed, nil
+}
+
+// Returns a new array holding a copy of the elements from index i up to but not including j, leaving the array unchanged
+func (a *RawArray) Slice(i, j int) (*RawArray, error) {
+	if i < 0 || j < i || j > len(*a) {
+		return nil, fmt.Errorf("array range [%d:%d] out of range", i, j)
+	}
+
+	arr := make(RawArray, j-i)
+	copy(arr, (*a)[i:j])
+
+	return &arr, nil
+}
+
+// Trims and checks values about to be added at index at, so that a bad one leaves the array untouched
+func validElements(at int, values []json.RawMessage) (RawArray, error) {
+	elems := make(RawArray, len(values))
+
+	for n, raw := range values {
+		raw = bytes.TrimSpace(raw)
+
+		if !json.Valid(raw) {
+			return nil, fmt.Errorf("element %d: %w", at+n, ErrInvalidJson)
+		}
+
+		elems[n] = raw
+	}
+
+	return elems, nil
+}
@@ -0,0 +1,329 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// A run of consecutive elements that an array diff treats alike
+type ArrayEdit struct {
+	// One of "unchanged", "inserted", or "deleted"; unordered diffs may also report "modified", and diffs under WithMoveDetection "moved"
+	Op string
+	// Index of the run's first element in the old array; for insertions, the old index the run precedes; for moves, the index moved from
+	OldIndex int
+	// Index of the run's first element in the new array; for deletions, the new index the run precedes; for moves, the index moved to
+	NewIndex int
+	Count    int
+}
+
+// this.Diff(that) aligns two arrays by longest common subsequence (Myers' algorithm) and returns runs of unchanged, inserted, and deleted elements in order
+//
+// Elements are equal when their compacted JSON is identical. Arrays too unlike for the alignment to be worth its cost, thousands of edits apart, are reported as every old element deleted and every new one inserted. Under WithUnorderedArrays the arrays are compared as multisets instead; see diffUnordered. Honors WithUnorderedArrays, WithArrayIdentity, and WithMoveDetection.
+func (a *RawArray) Diff(n *RawArray, opts ...Option) []ArrayEdit {
+	cfg := newConfig(opts)
+
+	if cfg.unorderedArrays {
+		return cfg.diffUnordered(*a, *n)
+	}
+
+	var (
+		old   = compactAll(*a)
+		new   = compactAll(*n)
+		edits = myers(old, new)
+	)
+
+	if cfg.detectMoves {
+		edits = moves(edits, old, new)
+	}
+
+	return coalesce(edits)
+}
+
+// Pairs deleted elements with inserted elements equal to them, earliest first, turning each pair into one move placed where the element was inserted
+func moves(edits []ArrayEdit, old, new []string) []ArrayEdit {
+	var (
+		deleted = make(map[string][]int)
+		from    = make(map[int]int)
+		moved   = make(map[int]bool)
+		out     = make([]ArrayEdit, 0, len(edits))
+	)
+
+	for _, e := range edits {
+		if e.Op == "deleted" {
+			deleted[old[e.OldIndex]] = append(deleted[old[e.OldIndex]], e.OldIndex)
+		}
+	}
+
+	for _, e := range edits {
+		if e.Op != "inserted" {
+			continue
+		}
+
+		if queue := deleted[new[e.NewIndex]]; len(queue) > 0 {
+			from[e.NewIndex] = queue[0]
+			moved[queue[0]] = true
+			deleted[new[e.NewIndex]] = queue[1:]
+		}
+	}
+
+	for _, e := range edits {
+		switch {
+		case e.Op == "deleted" && moved[e.OldIndex]:
+			continue
+		case e.Op == "inserted":
+			if i, ok := from[e.NewIndex]; ok {
+				e = ArrayEdit{Op: "moved", OldIndex: i, NewIndex: e.NewIndex, Count: 1}
+			}
+		}
+
+		out = append(out, e)
+	}
+
+	return out
+}
+
+// Matches elements regardless of position, by structural equality or by WithArrayIdentity
+//
+// Every edit covers one element. Matched elements are reported in old-array order as unchanged, or modified when matched by identity but otherwise different; unmatched old elements are deleted (NewIndex -1) and unmatched new elements inserted (OldIndex -1), in new-array order.
+func (c *config) diffUnordered(this, that RawArray) []ArrayEdit {
+	var (
+		edits   = make([]ArrayEdit, 0, len(this)+len(that))
+		pending = make(map[string][]int)
+		matched = make([]bool, len(that))
+	)
+
+	for j := range that {
+		key := c.identity(that[j])
+		pending[key] = append(pending[key], j)
+	}
+
+	for i := range this {
+		key := c.identity(this[i])
+		queue := pending[key]
+
+		if len(queue) == 0 {
+			edits = append(edits, ArrayEdit{Op: "deleted", OldIndex: i, NewIndex: -1, Count: 1})
+			continue
+		}
+
+		j := queue[0]
+		pending[key] = queue[1:]
+		matched[j] = true
+
+		op := "unchanged"
+		if !bytes.Equal(c.prune(this[i], ""), c.prune(that[j], "")) {
+			op = "modified"
+		}
+
+		edits = append(edits, ArrayEdit{Op: op, OldIndex: i, NewIndex: j, Count: 1})
+	}
+
+	for j := range that {
+		if !matched[j] {
+			edits = append(edits, ArrayEdit{Op: "inserted", OldIndex: -1, NewIndex: j, Count: 1})
+		}
+	}
+
+	return edits
+}
+
+// The key an element is matched on: its identity member when configured and present, otherwise its normalized form
+func (c *config) identity(elem json.RawMessage) string {
+	if c.arrayIdentity != "" {
+		if typ, _ := TypeOf(elem); *typ == "object" {
+			obj := make(RawObject)
+			json.Unmarshal(elem, &obj)
+
+			if id, ok := obj[c.arrayIdentity]; ok {
+				return "id:" + string(c.prune(id, ""))
+			}
+		}
+	}
+
+	return "value:" + string(c.prune(elem, ""))
+}
+
+// Compacts each element so that insignificant whitespace does not count as a change
+func compactAll(arr RawArray) []string {
+	keys := make([]string, len(arr))
+
+	for i := range arr {
+		var buf bytes.Buffer
+
+		if err := json.Compact(&buf, arr[i]); err != nil {
+			keys[i] = string(arr[i])
+		} else {
+			keys[i] = buf.String()
+		}
+	}
+
+	return keys
+}
+
+// How many rounds, each allowing one more edit, the search for where two ranges meet may take before myers gives up on them and reports the rest as replaced
+//
+// The search takes time proportional to the square of its rounds, so this bounds the work for two long, unrelated arrays.
+const maxMyersRounds = 1 << 12
+
+// Computes a shortest edit script between a and b, one element per edit, in space linear in their lengths
+//
+// Ranges are split where the forward and backward searches meet, as in Myers' refinement, and each half diffed in turn. A range whose edit distance exceeds twice maxMyersRounds is reported as its old elements deleted followed by its new elements inserted.
+func myers(a, b []string) []ArrayEdit {
+	d := &myersDiff{a: a, b: b, edits: make([]ArrayEdit, 0, len(a)+len(b))}
+	d.diff(0, len(a), 0, len(b))
+	return d.edits
+}
+
+// The inputs and the edit script so far of one myers call
+type myersDiff struct {
+	a, b  []string
+	edits []ArrayEdit
+}
+
+// Appends the edits turning a[x0:x1] into b[y0:y1]
+func (d *myersDiff) diff(x0, x1, y0, y1 int) {
+	for x0 < x1 && y0 < y1 && d.a[x0] == d.b[y0] {
+		d.edits = append(d.edits, ArrayEdit{Op: "unchanged", OldIndex: x0, NewIndex: y0, Count: 1})
+		x0++
+		y0++
+	}
+
+	suffix := 0
+	for x0 < x1-suffix && y0 < y1-suffix && d.a[x1-suffix-1] == d.b[y1-suffix-1] {
+		suffix++
+	}
+	x1, y1 = x1-suffix, y1-suffix
+
+	switch {
+	case x0 == x1 || y0 == y1:
+		d.replace(x0, x1, y0, y1)
+	default:
+		if x, y, ok := d.bisect(x0, x1, y0, y1); ok {
+			d.diff(x0, x, y0, y)
+			d.diff(x, x1, y, y1)
+		} else {
+			d.replace(x0, x1, y0, y1)
+		}
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, ArrayEdit{Op: "unchanged", OldIndex: x1 + i, NewIndex: y1 + i, Count: 1})
+	}
+}
+
+// Appends the deletion of a[x0:x1] and then the insertion of b[y0:y1]
+func (d *myersDiff) replace(x0, x1, y0, y1 int) {
+	for x := x0; x < x1; x++ {
+		d.edits = append(d.edits, ArrayEdit{Op: "deleted", OldIndex: x, NewIndex: y0, Count: 1})
+	}
+
+	for y := y0; y < y1; y++ {
+		d.edits = append(d.edits, ArrayEdit{Op: "inserted", OldIndex: x1, NewIndex: y, Count: 1})
+	}
+}
+
+// Searches from both ends of a[x0:x1] and b[y0:y1] at once for a point on a shortest edit path, reporting false if the searches do not meet within maxMyersRounds
+func (d *myersDiff) bisect(x0, x1, y0, y1 int) (int, int, bool) {
+	var (
+		n, m   = x1 - x0, y1 - y0
+		rounds = min((n+m+1)/2, maxMyersRounds)
+		offset = rounds
+		// The furthest x reached on each diagonal k, searching forwards and backwards, at index offset+k; -1 where no path has reached
+		fwd   = make([]int, 2*rounds+2)
+		bwd   = make([]int, 2*rounds+2)
+		delta = n - m
+		front = delta%2 != 0
+		// Diagonals that ran off the edge of the grid, and need not be searched again
+		fStart, fEnd, bStart, bEnd int
+	)
+
+	for i := range fwd {
+		fwd[i], bwd[i] = -1, -1
+	}
+	fwd[offset+1], bwd[offset+1] = 0, 0
+
+	for r := 0; r < rounds; r++ {
+		for k := -r + fStart; k <= r-fEnd; k += 2 {
+			var x int
+
+			if k == -r || (k != r && fwd[offset+k-1] < fwd[offset+k+1]) {
+				x = fwd[offset+k+1]
+			} else {
+				x = fwd[offset+k-1] + 1
+			}
+
+			y := x - k
+
+			for x < n && y < m && d.a[x0+x] == d.b[y0+y] {
+				x++
+				y++
+			}
+
+			fwd[offset+k] = x
+
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case front:
+				if i := offset + delta - k; i >= 0 && i < len(bwd) && bwd[i] != -1 && x >= n-bwd[i] {
+					return x0 + x, y0 + y, true
+				}
+			}
+		}
+
+		for k := -r + bStart; k <= r-bEnd; k += 2 {
+			var x int
+
+			if k == -r || (k != r && bwd[offset+k-1] < bwd[offset+k+1]) {
+				x = bwd[offset+k+1]
+			} else {
+				x = bwd[offset+k-1] + 1
+			}
+
+			y := x - k
+
+			for x < n && y < m && d.a[x1-x-1] == d.b[y1-y-1] {
+				x++
+				y++
+			}
+
+			bwd[offset+k] = x
+
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !front:
+				if i := offset + delta - k; i >= 0 && i < len(fwd) && fwd[i] != -1 {
+					fx := fwd[i]
+					fy := fx - (i - offset)
+
+					if fx >= n-x {
+						return x0 + fx, y0 + fy, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// Merges adjacent single-element edits of the same kind into runs; moves merge only when their elements stayed consecutive
+func coalesce(edits []ArrayEdit) []ArrayEdit {
+	runs := make([]ArrayEdit, 0)
+
+	for _, e := range edits {
+		if last := len(runs) - 1; last >= 0 && runs[last].Op == e.Op && (e.Op != "moved" || runs[last].OldIndex+runs[last].Count == e.OldIndex) {
+			runs[last].Count++
+			continue
+		}
+
+		runs = append(runs, e)
+	}
+
+	return runs
+}
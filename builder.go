@@ -0,0 +1,157 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Builds a JSON object member by member, for fabricating documents in tests and tools without string templates
+//
+// Members keep the order they were first set in. The first error, from a value that cannot be marshaled or raw bytes that are not JSON, is kept and returned by Build, and later calls do nothing.
+type ObjectBuilder struct {
+	obj OrderedRawObject
+	err error
+}
+
+// Builds a JSON array element by element, as ObjectBuilder builds objects
+type ArrayBuilder struct {
+	arr RawArray
+	err error
+}
+
+// Starts building an empty object
+func Obj() *ObjectBuilder {
+	return &ObjectBuilder{}
+}
+
+// Starts building an empty array
+func Arr() *ArrayBuilder {
+	return &ArrayBuilder{arr: make(RawArray, 0)}
+}
+
+// Sets key to value as json.Marshal encodes it, replacing any earlier value in place; builders and json.RawMessage may be given as values
+func (b *ObjectBuilder) Set(key string, value interface{}) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		b.err = fmt.Errorf("value for key %q: %w", key, err)
+		return b
+	}
+
+	b.obj.Set(key, raw)
+	return b
+}
+
+// Sets key to raw as written, replacing any earlier value in place; raw must be valid JSON
+func (b *ObjectBuilder) SetRaw(key string, raw json.RawMessage) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	raw = bytes.TrimSpace(raw)
+
+	if !json.Valid(raw) {
+		b.err = fmt.Errorf("value for key %q: %w", key, ErrInvalidJson)
+		return b
+	}
+
+	b.obj.Set(key, raw)
+	return b
+}
+
+// Returns the object built, or the first error met while building it
+func (b *ObjectBuilder) Build() (*RawObject, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.obj.RawObject(), nil
+}
+
+// Like Build, but keeps the members in the order they were set
+func (b *ObjectBuilder) Ordered() (*OrderedRawObject, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	obj := new(OrderedRawObject)
+	b.obj.Range(func(key string, value json.RawMessage) bool {
+		obj.Set(key, value)
+		return true
+	})
+
+	return obj, nil
+}
+
+// Implements json.Marshaler, writing members in the order they were set, so a builder can be the value of another
+func (b *ObjectBuilder) MarshalJSON() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.obj.MarshalJSON()
+}
+
+// Appends each value as json.Marshal encodes it; builders and json.RawMessage may be given as values
+func (b *ArrayBuilder) Append(values ...interface{}) *ArrayBuilder {
+	for _, value := range values {
+		if b.err != nil {
+			return b
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			b.err = fmt.Errorf("element %d: %w", len(b.arr), err)
+			return b
+		}
+
+		b.arr = append(b.arr, raw)
+	}
+
+	return b
+}
+
+// Appends each raw value as written; each must be valid JSON
+func (b *ArrayBuilder) AppendRaw(values ...json.RawMessage) *ArrayBuilder {
+	for _, raw := range values {
+		if b.err != nil {
+			return b
+		}
+
+		raw = bytes.TrimSpace(raw)
+
+		if !json.Valid(raw) {
+			b.err = fmt.Errorf("element %d: %w", len(b.arr), ErrInvalidJson)
+			return b
+		}
+
+		b.arr = append(b.arr, raw)
+	}
+
+	return b
+}
+
+// Returns the array built, or the first error met while building it
+func (b *ArrayBuilder) Build() (*RawArray, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	arr := make(RawArray, len(b.arr))
+	copy(arr, b.arr)
+
+	return &arr, nil
+}
+
+// Implements json.Marshaler, so a builder can be the value of another
+func (b *ArrayBuilder) MarshalJSON() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return json.Marshal(b.arr)
+}
@@ -0,0 +1,523 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// Reads a single CBOR value from a byte buffer and writes its JSON equivalent
+type cborDecoder struct {
+	data []byte
+	pos  int
+	out  bytes.Buffer
+}
+
+// Marks the end of an indefinite-length item, where a value would otherwise begin
+const cborBreak = 0xff
+
+// Transcodes one complete CBOR value into raw JSON
+func CborToJson(data []byte) ([]byte, error) {
+	dec := &cborDecoder{data: data}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty cbor input")
+	}
+
+	if err := dec.value(); err != nil {
+		return nil, err
+	}
+
+	if dec.pos != len(data) {
+		return nil, fmt.Errorf("trailing data after cbor value at offset %d", dec.pos)
+	}
+
+	return dec.out.Bytes(), nil
+}
+
+// Transcodes a CBOR sequence, as in RFC 8742, into one raw JSON value each
+func CborSequenceToJson(data []byte) ([]json.RawMessage, error) {
+	var (
+		dec  = &cborDecoder{data: data}
+		vals = make([]json.RawMessage, 0)
+	)
+
+	for dec.pos < len(data) {
+		if err := dec.value(); err != nil {
+			return vals, err
+		}
+
+		vals = append(vals, append(json.RawMessage(nil), dec.out.Bytes()...))
+		dec.out.Reset()
+	}
+
+	return vals, nil
+}
+
+// Generates a populated JsonDescription from raw CBOR
+func DescribeCbor(data []byte) (*JsonDescription, error) {
+	js, err := CborToJson(data)
+
+	if err != nil {
+		return NewJsonDescription(), err
+	}
+
+	return Describe(js)
+}
+
+// Consumes n bytes, failing if the input is too short
+func (d *cborDecoder) take(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, fmt.Errorf("unexpected end of cbor input at offset %d", d.pos)
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// Reads the argument of an initial byte whose additional information is info; indefinite is set instead for 31, which only strings and containers allow
+func (d *cborDecoder) argument(info byte) (arg uint64, indefinite bool, err error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info <= 27:
+		raw, err := d.take(1 << (info - 24))
+		if err != nil {
+			return 0, false, err
+		}
+		return beUint(raw), false, nil
+	case info == 31:
+		return 0, true, nil
+	}
+
+	return 0, false, fmt.Errorf("invalid cbor additional information %d at offset %d", info, d.pos-1)
+}
+
+// Reads a definite length, failing when it cannot possibly fit in the rest of the input
+func (d *cborDecoder) length(arg uint64) (int, error) {
+	if arg > uint64(len(d.data)-d.pos) {
+		return 0, fmt.Errorf("unexpected end of cbor input at offset %d", d.pos)
+	}
+
+	return int(arg), nil
+}
+
+// Transcodes the value at the current position
+func (d *cborDecoder) value() error {
+	b, err := d.take(1)
+
+	if err != nil {
+		return err
+	}
+
+	var (
+		major = b[0] >> 5
+		info  = b[0] & 0x1f
+	)
+
+	if major == 7 {
+		return d.simple(info)
+	}
+
+	arg, indefinite, err := d.argument(info)
+
+	if err != nil {
+		return err
+	}
+
+	if indefinite && (major < 2 || major == 6) {
+		return fmt.Errorf("invalid indefinite-length cbor item at offset %d", d.pos-1)
+	}
+
+	switch major {
+	case 0:
+		d.out.WriteString(strconv.FormatUint(arg, 10))
+	case 1:
+		d.out.WriteString(cborNegative(arg))
+	case 2, 3:
+		raw, err := d.content(major, arg, indefinite)
+		if err != nil {
+			return err
+		}
+		if major == 2 {
+			d.out.WriteByte('"')
+			d.out.WriteString(base64.StdEncoding.EncodeToString(raw))
+			d.out.WriteByte('"')
+		} else {
+			enc, _ := json.Marshal(string(raw))
+			d.out.Write(enc)
+		}
+	case 4:
+		return d.array(arg, indefinite)
+	case 5:
+		return d.object(arg, indefinite)
+	case 6:
+		return d.tagged(arg)
+	}
+
+	return nil
+}
+
+// Writes -1-n, which may lie below the range of int64
+func cborNegative(n uint64) string {
+	if n <= math.MaxInt64 {
+		return strconv.FormatInt(-1-int64(n), 10)
+	}
+
+	v := new(big.Int).SetUint64(n)
+	return v.Neg(v.Add(v, big.NewInt(1))).String()
+}
+
+// Reads the contents of a byte or text string, joining the chunks of an indefinite-length one
+func (d *cborDecoder) content(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		n, err := d.length(arg)
+		if err != nil {
+			return nil, err
+		}
+		return d.take(n)
+	}
+
+	var joined []byte
+
+	for {
+		if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+			d.pos++
+			return joined, nil
+		}
+
+		b, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if b[0]>>5 != major || b[0]&0x1f == 31 {
+			return nil, fmt.Errorf("invalid chunk in indefinite-length cbor string at offset %d", d.pos-1)
+		}
+
+		arg, _, err := d.argument(b[0] & 0x1f)
+		if err != nil {
+			return nil, err
+		}
+
+		chunk, err := d.content(major, arg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		joined = append(joined, chunk...)
+	}
+}
+
+// Writes a simple value or float; JSON has no representation for NaN or infinities, and undefined becomes null
+func (d *cborDecoder) simple(info byte) error {
+	var f float64
+
+	switch info {
+	case 20:
+		d.out.WriteString("false")
+		return nil
+	case 21:
+		d.out.WriteString("true")
+		return nil
+	case 22, 23:
+		d.out.WriteString("null")
+		return nil
+	case 25:
+		raw, err := d.take(2)
+		if err != nil {
+			return err
+		}
+		f = halfFloat(binary.BigEndian.Uint16(raw))
+	case 26:
+		raw, err := d.take(4)
+		if err != nil {
+			return err
+		}
+		f = float64(math.Float32frombits(binary.BigEndian.Uint32(raw)))
+	case 27:
+		raw, err := d.take(8)
+		if err != nil {
+			return err
+		}
+		f = math.Float64frombits(binary.BigEndian.Uint64(raw))
+	case 31:
+		return fmt.Errorf("unexpected cbor break at offset %d", d.pos-1)
+	default:
+		return fmt.Errorf("unsupported cbor simple value %d at offset %d", info, d.pos-1)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("cbor float %v at offset %d has no json equivalent", f, d.pos)
+	}
+
+	bits := 64
+	if info < 27 {
+		bits = 32
+	}
+
+	d.out.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	return nil
+}
+
+// Widens an IEEE 754 half-precision float
+func halfFloat(h uint16) float64 {
+	var (
+		exp  = int(h>>10) & 0x1f
+		mant = float64(h & 0x3ff)
+		f    float64
+	)
+
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -f
+	}
+
+	return f
+}
+
+// Writes a tagged value: an epoch time as an RFC 3339 string, a bignum as a number, and anything else as the value it tags
+func (d *cborDecoder) tagged(tag uint64) error {
+	switch tag {
+	case 1:
+		start := d.out.Len()
+		if err := d.value(); err != nil {
+			return err
+		}
+
+		secs, err := strconv.ParseFloat(string(d.out.Bytes()[start:]), 64)
+		if err != nil {
+			return fmt.Errorf("invalid cbor epoch time at offset %d", d.pos)
+		}
+
+		whole, frac := math.Modf(secs)
+		ts := time.Unix(int64(whole), int64(frac*1e9))
+
+		d.out.Truncate(start)
+		d.out.WriteString(strconv.Quote(ts.UTC().Format(time.RFC3339Nano)))
+		return nil
+
+	case 2, 3:
+		b, err := d.take(1)
+		if err != nil {
+			return err
+		}
+
+		arg, indefinite, err := d.argument(b[0] & 0x1f)
+		if err != nil || b[0]>>5 != 2 {
+			return fmt.Errorf("invalid cbor bignum at offset %d", d.pos-1)
+		}
+
+		raw, err := d.content(2, arg, indefinite)
+		if err != nil {
+			return err
+		}
+
+		v := new(big.Int).SetBytes(raw)
+		if tag == 3 {
+			v.Neg(v.Add(v, big.NewInt(1)))
+		}
+
+		d.out.WriteString(v.String())
+		return nil
+	}
+
+	return d.value()
+}
+
+// Writes n consecutive cbor values, or values up to a break, as a JSON array
+func (d *cborDecoder) array(n uint64, indefinite bool) error {
+	d.out.WriteByte('[')
+
+	for i := uint64(0); indefinite || i < n; i++ {
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("unexpected end of cbor input at offset %d", d.pos)
+		}
+
+		if indefinite && d.data[d.pos] == cborBreak {
+			d.pos++
+			break
+		}
+
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+
+		if err := d.value(); err != nil {
+			return err
+		}
+	}
+
+	d.out.WriteByte(']')
+	return nil
+}
+
+// Writes n cbor key/value pairs, or pairs up to a break, as a JSON object; integer keys become their decimal strings
+func (d *cborDecoder) object(n uint64, indefinite bool) error {
+	d.out.WriteByte('{')
+
+	for i := uint64(0); indefinite || i < n; i++ {
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("unexpected end of cbor input at offset %d", d.pos)
+		}
+
+		if indefinite && d.data[d.pos] == cborBreak {
+			d.pos++
+			break
+		}
+
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+
+		var (
+			start = d.out.Len()
+			major = d.data[d.pos] >> 5
+		)
+
+		if err := d.value(); err != nil {
+			return err
+		}
+
+		switch major {
+		case 3:
+		case 0, 1:
+			quoted := strconv.Quote(string(d.out.Bytes()[start:]))
+			d.out.Truncate(start)
+			d.out.WriteString(quoted)
+		default:
+			return fmt.Errorf("unsupported cbor map key of major type %d", major)
+		}
+
+		d.out.WriteByte(':')
+		if err := d.value(); err != nil {
+			return err
+		}
+	}
+
+	d.out.WriteByte('}')
+	return nil
+}
+
+// Encodes raw JSON as a single CBOR value, using the shortest form of every integer and length
+func JsonToCbor(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	err := encodeCbor(&out, bytes.TrimSpace(data))
+	return out.Bytes(), err
+}
+
+func encodeCbor(out *bytes.Buffer, raw json.RawMessage) error {
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+		cborHeader(out, 5, uint64(len(members)))
+		for _, m := range members {
+			cborHeader(out, 3, uint64(len(m.Key)))
+			out.WriteString(m.Key)
+			if err = encodeCbor(out, m.Value); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+		cborHeader(out, 4, uint64(len(arr)))
+		for i := range arr {
+			if err := encodeCbor(out, arr[i]); err != nil {
+				return err
+			}
+		}
+	case "string":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		cborHeader(out, 3, uint64(len(s)))
+		out.WriteString(s)
+	case "number":
+		return cborNumber(out, string(raw))
+	case "true":
+		out.WriteByte(0xf5)
+	case "false":
+		out.WriteByte(0xf4)
+	case "null":
+		out.WriteByte(0xf6)
+	}
+
+	return nil
+}
+
+// Writes an initial byte of the given major type with its argument in the fewest bytes
+func cborHeader(out *bytes.Buffer, major byte, arg uint64) {
+	major <<= 5
+
+	switch {
+	case arg < 24:
+		out.WriteByte(major | byte(arg))
+	case arg <= math.MaxUint8:
+		out.Write([]byte{major | 24, byte(arg)})
+	case arg <= math.MaxUint16:
+		out.WriteByte(major | 25)
+		out.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		out.WriteByte(major | 26)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		out.WriteByte(major | 27)
+		out.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+// Writes a JSON number as a cbor integer when it is one, otherwise as a float64
+func cborNumber(out *bytes.Buffer, num string) error {
+	if u, err := strconv.ParseUint(num, 10, 64); err == nil {
+		cborHeader(out, 0, u)
+		return nil
+	}
+
+	if i, err := strconv.ParseInt(num, 10, 64); err == nil && i < 0 {
+		cborHeader(out, 1, uint64(-1-i))
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+
+	if err != nil {
+		return fmt.Errorf("number %s cannot be represented in cbor", num)
+	}
+
+	out.WriteByte(0xfb)
+	out.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return nil
+}
@@ -0,0 +1,93 @@
+package jsondescriber
+
+// The bytes written to a Describer so far, and how far the value in them has got
+type chunks struct {
+	data     []byte
+	depth    int
+	started  bool
+	scalar   bool
+	inString bool
+	escaped  bool
+	complete bool
+}
+
+// Appends p to the document being described, for feeding a Describer bytes as they arrive; Finalize describes them once the value is complete
+//
+// Write implements io.Writer, so a Describer can be the destination of io.Copy. It fails, keeping none of p, once the bytes written would exceed WithMaxBytes. Honors WithMaxBytes.
+func (d *Describer) Write(p []byte) (int, error) {
+	if d.cfg.maxBytes > 0 && len(d.chunks.data)+len(p) > d.cfg.maxBytes {
+		return 0, &LimitError{Limit: "bytes", Max: d.cfg.maxBytes, Offset: int64(d.cfg.maxBytes)}
+	}
+
+	d.chunks.data = append(d.chunks.data, p...)
+
+	for _, ch := range p {
+		d.chunks.track(ch)
+	}
+
+	return len(p), nil
+}
+
+// Reports whether the bytes written so far hold a complete top-level value, so a handler can stop reading and call Finalize
+//
+// Only the nesting of brackets and strings is followed, not the validity of what is between them, which Finalize checks. A top-level number or literal is complete only once whitespace follows it, as it could otherwise go on in the next chunk.
+func (d *Describer) Complete() bool {
+	return d.chunks.complete
+}
+
+// Describes the bytes written since the last Finalize, Describe, or Reset, as Describe would describe them all at once, and starts a new document
+//
+// The result belongs to the Describer as Describe's does. A value left incomplete is reported as invalid JSON, like any other truncated document.
+func (d *Describer) Finalize() (*JsonDescription, error) {
+	data := d.chunks.data
+	d.chunks = chunks{}
+
+	descr, err := d.Describe(data)
+
+	// Keep the buffer for the next document, unless it grew too large to be worth holding on to
+	if cap(data) <= maxRetainedChunkBytes {
+		d.chunks.data = data[:0]
+	}
+
+	return descr, err
+}
+
+// Largest buffer of written bytes a Describer keeps for reuse after Finalize
+const maxRetainedChunkBytes = 1 << 20
+
+// Follows one byte of the document to tell when its top-level value ends
+func (c *chunks) track(ch byte) {
+	switch {
+	case c.complete:
+
+	case c.inString:
+		switch {
+		case c.escaped:
+			c.escaped = false
+		case ch == '\\':
+			c.escaped = true
+		case ch == '"':
+			c.inString = false
+			c.complete = c.depth == 0
+		}
+
+	case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+		c.complete = c.scalar
+
+	case ch == '"':
+		c.started = true
+		c.inString = true
+
+	case ch == '{' || ch == '[':
+		c.started = true
+		c.depth++
+
+	case ch == '}' || ch == ']':
+		c.depth--
+		c.complete = c.depth <= 0
+
+	case !c.started:
+		c.started = true
+		c.scalar = true
+	}
+}
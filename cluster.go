@@ -0,0 +1,84 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A family of structurally similar documents found by Cluster
+type Family struct {
+	// Positions of the member documents in the slice given to Cluster, in order
+	Documents []int
+	// Position of the document the others were compared to: the first of the family
+	Representative int
+	// The Fingerprint of the representative
+	Fingerprint string
+	// Describes the representative
+	Description *JsonDescription
+	// The merged shape of every member, showing which keys the family always or only sometimes has
+	Shape *Shape
+}
+
+// Groups documents into structural families, largest first, for triaging a heterogeneous corpus
+//
+// Documents sharing a Fingerprint always fall together. Otherwise each document joins the family whose representative it is most Similar to, if that similarity reaches WithClusterThreshold, or else starts a family of its own. Ties keep families in order of first appearance. Honors WithClusterThreshold, and the options Describe honors for each Description.
+func Cluster(docs [][]byte, opts ...Option) ([]*Family, error) {
+	var (
+		cfg      = newConfig(opts)
+		clusters = make([]*Family, 0)
+		byPrint  = make(map[string]*Family)
+		paths    = make([]map[string]bool, 0)
+		aggs     = make([]*Aggregator, 0)
+	)
+
+	for i, data := range docs {
+		print, err := Fingerprint(data)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		c, ok := byPrint[print]
+
+		if !ok {
+			set, _ := pathSet(data)
+			best, score := -1, 0.0
+
+			for j := range clusters {
+				if s := jaccard(set, paths[j]); s >= cfg.clusterThreshold && s > score {
+					best, score = j, s
+				}
+			}
+
+			if best >= 0 {
+				c = clusters[best]
+			} else {
+				descr, err := Describe(data, opts...)
+				if err != nil {
+					return nil, fmt.Errorf("document %d: %w", i, err)
+				}
+
+				c = &Family{Documents: make([]int, 0), Representative: i, Fingerprint: print, Description: descr}
+				clusters = append(clusters, c)
+				paths = append(paths, set)
+				aggs = append(aggs, NewAggregator())
+			}
+
+			byPrint[print] = c
+		}
+
+		c.Documents = append(c.Documents, i)
+	}
+
+	for j, c := range clusters {
+		for _, i := range c.Documents {
+			aggs[j].Add(docs[i])
+		}
+		c.Shape = aggs[j].Shape()
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Documents) > len(clusters[j].Documents)
+	})
+
+	return clusters, nil
+}
@@ -0,0 +1,702 @@
+// Command jsondescribe exposes the jsondescriber package on the command line
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andyborne/jsondescriber"
+)
+
+const usage = `usage: jsondescribe <command> [flags]
+
+commands:
+  codegen   generate type declarations from sample documents
+  convert   convert a document between formats
+  diff      show the members changed between two objects
+  histogram count the types seen at every path of an NDJSON stream
+  table     print an array of objects as an aligned table
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "codegen":
+		err = codegen(os.Args[2:])
+	case "convert":
+		err = convert(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	case "histogram":
+		err = histogram(os.Args[2:])
+	case "table":
+		err = table(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "jsondescribe: unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsondescribe: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Formats each side of convert understands
+var (
+	readable = map[string]bool{"json": true, "ndjson": true, "cbor": true, "msgpack": true, "toml": true, "csv": true}
+	writable = map[string]bool{"json": true, "ndjson": true, "cbor": true, "msgpack": true, "yaml": true, "csv": true}
+)
+
+// Prints the members changed from one object to another, colored on a terminal
+func diff(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("diff", flag.ContinueOnError)
+		color    = flags.String("color", "auto", "color output: auto, always, or never")
+		truncate = flags.Int("truncate", 80, "cut values longer than this many bytes (0: never)")
+		unified  = flags.Bool("unified", false, "print a unified diff of the pretty-printed documents, which need not be objects")
+		side     = flags.Bool("side", false, "print old and new values side by side")
+		width    = flags.Int("width", 120, "width of -side output")
+		opts     = []jsondescriber.Option{}
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsondescribe diff [flags] old new")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("need two files to compare")
+	}
+
+	switch *color {
+	case "always":
+		opts = append(opts, jsondescriber.WithColor(true))
+	case "never":
+		opts = append(opts, jsondescriber.WithColor(false))
+	case "auto":
+	default:
+		return fmt.Errorf("unknown -color %q", *color)
+	}
+
+	opts = append(opts, jsondescriber.WithTruncate(*truncate))
+
+	var (
+		docs [2][]byte
+		objs [2]*jsondescriber.RawObject
+	)
+
+	for i := range docs {
+		data, err := os.ReadFile(flags.Arg(i))
+		if err != nil {
+			return err
+		}
+		docs[i] = bytes.TrimSpace(data)
+	}
+
+	if *unified {
+		out, err := jsondescriber.UnifiedDiff(docs[0], docs[1], jsondescriber.WithDiffLabels(flags.Arg(0), flags.Arg(1)))
+		fmt.Print(out)
+		return err
+	}
+
+	for i := range objs {
+		var err error
+		if objs[i], err = jsondescriber.UnmarshalObject(docs[i]); err != nil {
+			return fmt.Errorf("%s: %w", flags.Arg(i), err)
+		}
+	}
+
+	changes := objs[0].DiffDetailed(objs[1], opts...)
+
+	if *side {
+		fmt.Print(changes.SideBySide(jsondescriber.WithWidth(*width)))
+		return nil
+	}
+
+	return changes.Render(os.Stdout, opts...)
+}
+
+// Prints how often each path is present across the lines of an NDJSON input, and which types it holds
+func histogram(args []string) error {
+	var (
+		flags  = flag.NewFlagSet("histogram", flag.ContinueOnError)
+		dotted = flags.Bool("dotted", false, "write paths as user.tags[*] instead of JSON Pointers")
+		opts   = []jsondescriber.Option{}
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsondescribe histogram [flags] [input]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	if *dotted {
+		opts = append(opts, jsondescriber.WithPathStyle(jsondescriber.DottedPath))
+	}
+
+	in := os.Stdin
+
+	if name := flags.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	agg := jsondescriber.NewAggregator()
+
+	if err := agg.AddReader(in, jsondescriber.WithDecompression()); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tCOUNT\tPRESENCE\tTYPES")
+
+	for _, h := range agg.Shape().Histograms(opts...) {
+		types := make([]string, 0, len(h.Types))
+		for _, t := range sortedTypes(h.Types) {
+			types = append(types, fmt.Sprintf("%s %d", t, h.Types[t]))
+		}
+
+		path := h.Path
+		if path == "" {
+			path = "(document)"
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%s\n", path, h.Count, h.Presence*100, strings.Join(types, ", "))
+	}
+
+	if n := agg.Errors(); n > 0 {
+		fmt.Fprintf(os.Stderr, "jsondescribe: skipped invalid json lines: %d\n", n)
+	}
+
+	return tw.Flush()
+}
+
+func codegen(args []string) error {
+	var (
+		flags = flag.NewFlagSet("codegen", flag.ContinueOnError)
+		lang  = flags.String("lang", "go", "language to generate: go, typescript, avro, proto, openapi, bigquery, elasticsearch, or parquet")
+		name  = flags.String("name", "Document", "name of the type describing a whole document")
+		pkg   = flags.String("package", "", "package of generated go, main by default, or protobuf")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsondescribe codegen [flags] [input]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	in := os.Stdin
+
+	if file := flags.Arg(0); file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	agg := jsondescriber.NewAggregator()
+
+	if err := agg.AddReader(in, jsondescriber.WithDecompression()); err != nil {
+		return err
+	}
+
+	if n := agg.Errors(); n > 0 {
+		fmt.Fprintf(os.Stderr, "jsondescribe: skipped invalid json lines: %d\n", n)
+	}
+
+	var (
+		out []byte
+		err error
+	)
+
+	switch *lang {
+	case "go":
+		out, err = agg.Shape().GoTypes(*name, jsondescriber.WithPackageName(*pkg))
+	case "typescript", "ts":
+		out = agg.Shape().TypeScriptTypes(*name)
+	case "avro":
+		out, err = agg.Shape().AvroSchema(*name)
+	case "proto", "protobuf":
+		out = agg.Shape().ProtoMessages(*name, jsondescriber.WithPackageName(*pkg))
+	case "openapi":
+		out, err = agg.Shape().OpenAPISchemas(*name)
+	case "bigquery":
+		out, err = agg.Shape().BigQuerySchema()
+	case "elasticsearch":
+		out, err = agg.Shape().ElasticsearchMapping()
+	case "parquet":
+		out, err = agg.Shape().ParquetSchema(*name)
+	default:
+		return fmt.Errorf("unknown language %q", *lang)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// Prints the objects of a JSON array as a table, one row each
+func table(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("table", flag.ContinueOnError)
+		columns  = flags.String("columns", "", "comma-separated keys to print, in order (default: every key)")
+		truncate = flags.Int("truncate", 40, "cut cells longer than this many bytes (0: never)")
+		types    = flags.Bool("types", false, "head each column with the types of its values")
+		opts     = []jsondescriber.Option{}
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsondescribe table [flags] [input]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	if *columns != "" {
+		opts = append(opts, jsondescriber.WithColumns(strings.Split(*columns, ",")...))
+	}
+
+	if *types {
+		opts = append(opts, jsondescriber.WithTypeAnnotations())
+	}
+
+	opts = append(opts, jsondescriber.WithTruncate(*truncate))
+
+	in := os.Stdin
+
+	if name := flags.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	arr, err := jsondescriber.UnmarshalArray(bytes.TrimSpace(data))
+	if err != nil {
+		return err
+	}
+
+	return arr.Table(os.Stdout, opts...)
+}
+
+// Lists type names by count, most common first
+func sortedTypes(counts map[string]uint) []string {
+	names := make([]string, 0, len(counts))
+
+	for t := range counts {
+		names = append(names, t)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// Maps file extensions to format names
+var extensions = map[string]string{
+	".json":    "json",
+	".ndjson":  "ndjson",
+	".jsonl":   "ndjson",
+	".cbor":    "cbor",
+	".msgpack": "msgpack",
+	".mpk":     "msgpack",
+	".toml":    "toml",
+	".yaml":    "yaml",
+	".yml":     "yaml",
+	".csv":     "csv",
+}
+
+// Yields each value of an input; stream is set when the input is a sequence rather than one document
+type source struct {
+	stream bool
+	each   func(emit func(json.RawMessage) error) error
+}
+
+func convert(args []string) error {
+	var (
+		flags  = flag.NewFlagSet("convert", flag.ContinueOnError)
+		from   = flags.String("from", "", "input format: json, ndjson, cbor, msgpack, toml, or csv; yaml is output only for now (default: from extension, else json)")
+		to     = flags.String("to", "", "output format: json, ndjson, cbor, msgpack, yaml, or csv (default: from extension, else json)")
+		indent = flags.String("indent", "", "indent json output with this string")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: jsondescribe convert [flags] [input [output]]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 2 {
+		flags.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	in, out := os.Stdin, os.Stdout
+
+	if name := flags.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		*from = formatOf(*from, name)
+	}
+
+	if name := flags.Arg(1); name != "" && name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+		*to = formatOf(*to, name)
+	}
+
+	*from = formatOf(*from, "")
+	*to = formatOf(*to, "")
+
+	if *from == "yaml" {
+		return fmt.Errorf("cannot read yaml yet, only write it")
+	}
+
+	if !readable[*from] {
+		return fmt.Errorf("cannot read format %q", *from)
+	}
+
+	if !writable[*to] {
+		return fmt.Errorf("cannot write format %q", *to)
+	}
+
+	src, err := open(*from, *to, bufio.NewReader(in))
+
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+
+	if err = write(*to, *indent, src, w); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// Resolves an explicit format, falling back to the file extension and then json
+func formatOf(explicit, name string) string {
+	if explicit != "" {
+		return strings.ToLower(explicit)
+	}
+
+	if f, ok := extensions[strings.ToLower(filepath.Ext(name))]; ok {
+		return f
+	}
+
+	if name != "" {
+		return ""
+	}
+
+	return "json"
+}
+
+// Prepares a source for the input; a cbor or msgpack sequence of one value is a document, and a single JSON array bound for ndjson is streamed element by element
+func open(from, to string, r *bufio.Reader) (source, error) {
+	switch from {
+	case "ndjson":
+		return source{stream: true, each: func(emit func(json.RawMessage) error) error {
+			return decodeAll(json.NewDecoder(r), emit)
+		}}, nil
+
+	case "cbor", "msgpack":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return source{}, err
+		}
+		sequence := jsondescriber.MsgpackSequenceToJson
+		if from == "cbor" {
+			sequence = jsondescriber.CborSequenceToJson
+		}
+		vals, err := sequence(data)
+		if err != nil {
+			return source{}, err
+		}
+		return source{stream: len(vals) != 1, each: func(emit func(json.RawMessage) error) error {
+			for _, v := range vals {
+				if err := emit(v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}, nil
+
+	case "csv":
+		rows, err := jsondescriber.ReadCSV(r, jsondescriber.WithTypeInference())
+		if err != nil {
+			return source{}, err
+		}
+		return source{stream: true, each: func(emit func(json.RawMessage) error) error {
+			for _, row := range *rows {
+				if err := emit(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}, nil
+
+	case "toml":
+		return source{each: func(emit func(json.RawMessage) error) error {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			js, err := jsondescriber.TomlToJson(data)
+			if err != nil {
+				return err
+			}
+			return emit(js)
+		}}, nil
+	}
+
+	if to == "ndjson" && firstByte(r) == '[' {
+		return source{stream: true, each: func(emit func(json.RawMessage) error) error {
+			dec := json.NewDecoder(r)
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			if err := decodeAll(dec, emit); err != nil {
+				return err
+			}
+			_, err := dec.Token()
+			return err
+		}}, nil
+	}
+
+	return source{each: func(emit func(json.RawMessage) error) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if _, err = jsondescriber.TypeOf(bytes.TrimSpace(data)); err != nil {
+			return err
+		}
+		return emit(bytes.TrimSpace(data))
+	}}, nil
+}
+
+// Peeks at the first non-whitespace byte without consuming it
+func firstByte(r *bufio.Reader) byte {
+	for n := 1; ; n++ {
+		b, err := r.Peek(n)
+		if err != nil {
+			return 0
+		}
+		if c := b[n-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c
+		}
+	}
+}
+
+// Emits every remaining value from a decoder
+func decodeAll(dec *json.Decoder, emit func(json.RawMessage) error) error {
+	for dec.More() {
+		var v json.RawMessage
+
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+
+		if err := emit(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes every value from src in the output format; streams become a JSON array when written as json, and a document each when written as yaml
+func write(to, indent string, src source, w io.Writer) error {
+	var (
+		buf   bytes.Buffer
+		first = true
+	)
+
+	if to == "csv" {
+		return writeCSV(src, w)
+	}
+
+	err := src.each(func(v json.RawMessage) error {
+		buf.Reset()
+
+		switch to {
+		case "json":
+			if src.stream {
+				if first {
+					io.WriteString(w, "[")
+				} else {
+					io.WriteString(w, ",")
+				}
+				if indent != "" {
+					io.WriteString(w, "\n"+indent)
+				}
+			}
+			var err error
+			if indent != "" {
+				prefix := ""
+				if src.stream {
+					prefix = indent
+				}
+				err = json.Indent(&buf, v, prefix, indent)
+			} else {
+				err = json.Compact(&buf, v)
+			}
+			if err != nil {
+				return err
+			}
+			if !src.stream {
+				buf.WriteByte('\n')
+			}
+
+		case "ndjson":
+			if err := json.Compact(&buf, v); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+
+		case "cbor":
+			cb, err := jsondescriber.JsonToCbor(v)
+			if err != nil {
+				return err
+			}
+			buf.Write(cb)
+
+		case "msgpack":
+			mp, err := jsondescriber.JsonToMsgpack(v)
+			if err != nil {
+				return err
+			}
+			buf.Write(mp)
+
+		case "yaml":
+			y, err := jsondescriber.JsonToYaml(v)
+			if err != nil {
+				return err
+			}
+			if src.stream {
+				buf.WriteString("---\n")
+			}
+			buf.Write(y)
+		}
+
+		first = false
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if to == "json" && src.stream {
+		switch {
+		case first:
+			io.WriteString(w, "[]\n")
+		case indent != "":
+			io.WriteString(w, "\n]\n")
+		default:
+			io.WriteString(w, "]\n")
+		}
+	}
+
+	return nil
+}
+
+// Writes the objects of src as CSV rows: the elements of a stream, or of a single array
+func writeCSV(src source, w io.Writer) error {
+	rows := make(jsondescriber.RawArray, 0)
+
+	err := src.each(func(v json.RawMessage) error {
+		if src.stream {
+			rows = append(rows, v)
+			return nil
+		}
+		return json.Unmarshal(v, &rows)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return rows.ToCSV(w)
+}
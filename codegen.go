@@ -0,0 +1,158 @@
+package jsondescriber
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// What a Shape's values amount to for a typed language, ignoring nulls
+type valueKind int
+
+const (
+	// Nothing but nulls, or nothing at all, was seen
+	kindUnknown valueKind = iota
+	kindObject
+	kindArray
+	kindString
+	// Numbers, every one written without a fraction or exponent
+	kindInteger
+	kindNumber
+	// true and false together
+	kindBoolean
+	// Values of more than one of the kinds above
+	kindMixed
+)
+
+var valueKindNames = [...]string{"unknown", "object", "array", "string", "integer", "number", "boolean", "mixed"}
+
+func (k valueKind) String() string {
+	return valueKindNames[k]
+}
+
+// Decides which kind the non-null values at this location share
+func (s *Shape) kind() valueKind {
+	var (
+		kind  = kindUnknown
+		found = func(k valueKind) {
+			if kind == kindUnknown || kind == k {
+				kind = k
+			} else {
+				kind = kindMixed
+			}
+		}
+	)
+
+	for name, n := range s.Types {
+		if n == 0 {
+			continue
+		}
+
+		switch name {
+		case "object":
+			found(kindObject)
+		case "array":
+			found(kindArray)
+		case "string":
+			found(kindString)
+		case "number":
+			if s.Integers == n {
+				found(kindInteger)
+			} else {
+				found(kindNumber)
+			}
+		case "true", "false":
+			found(kindBoolean)
+		}
+	}
+
+	return kind
+}
+
+// Initialisms written in capitals within generated names, as Go style has them
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "TCP": true, "TLS": true, "TTL": true,
+	"UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// Turns a key into an exported identifier, e.g. "user_id" into "UserID" and "2fa" into "X2fa"
+func exportedName(key string) string {
+	var sb strings.Builder
+
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+
+	name := sb.String()
+
+	if name == "" {
+		return "Field"
+	}
+
+	if first := []rune(name)[0]; !unicode.IsLetter(first) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// Returns name, or name with the lowest numeric suffix from 2 that is not yet used, and marks it used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+
+	used[unique] = true
+	return unique
+}
+
+// Names most schema languages accept for fields and types
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Reports whether the objects here are keyed by data, such as dates or IDs, rather than by field names, judged by none of their keys being an identifier
+func (s *Shape) keyedByValue() bool {
+	return len(s.Keys) > 0 && len(s.filterKeys(identifier.MatchString)) == 0
+}
+
+// Merges the shapes of every member of the objects here, as the values of a map
+func (s *Shape) memberValues() *Shape {
+	values := NewShape()
+
+	for _, k := range s.Keys {
+		values.merge(s.Fields[k])
+	}
+
+	return values
+}
+
+// Replaces the characters of key not allowed in an identifier with underscores, prefixing one if it starts with a digit
+func identifierFor(key string) string {
+	name := []byte(key)
+
+	for i, c := range name {
+		if !(c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			name[i] = '_'
+		}
+	}
+
+	if len(name) == 0 || '0' <= name[0] && name[0] <= '9' {
+		name = append([]byte{'_'}, name...)
+	}
+
+	return string(name)
+}
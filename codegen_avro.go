@@ -0,0 +1,136 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+type avroRecord struct {
+	Type   string      `json:"type"`
+	Name   string      `json:"name"`
+	Fields []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Doc     string          `json:"doc,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroArray struct {
+	Type  string      `json:"type"`
+	Items interface{} `json:"items"`
+}
+
+type avroMap struct {
+	Type   string      `json:"type"`
+	Values interface{} `json:"values"`
+}
+
+// Generates an Avro schema that can hold every document the shape was inferred from, whose top-level type is called name when it is a record
+//
+// Objects become records named after their path, as GoTypes names structs, except that objects none of whose keys Avro could use as a field name, such as objects keyed by date or ID, become maps with their values merged into one type. Other keys Avro cannot use are renamed with underscores, noting the JSON key in the field's doc. Members some objects lack, and nullable members, become unions with null that default to null. Numbers that were always integers become long, other numbers double, and a location that held values of several types gets a union of them.
+func (s *Shape) AvroSchema(name string) ([]byte, error) {
+	gen := &avroGen{used: make(map[string]bool)}
+
+	return json.MarshalIndent(gen.schema(s, exportedName(name)), "", "  ")
+}
+
+// Tracks the record names used so far, which Avro requires to be unique within a schema
+type avroGen struct {
+	used map[string]bool
+}
+
+// Builds the schema for the values at a location, a union when they were of several types or nullable; name is used for a record
+func (g *avroGen) schema(s *Shape, name string) interface{} {
+	var union []interface{}
+
+	if s.Nullable() {
+		union = append(union, "null")
+	}
+
+	if s.Types["object"] > 0 {
+		union = append(union, g.object(s, name))
+	}
+
+	if s.Types["array"] > 0 {
+		var items interface{} = "null"
+		if s.Items != nil {
+			items = g.schema(s.Items, name+"Item")
+		}
+		union = append(union, avroArray{Type: "array", Items: items})
+	}
+
+	if s.Types["string"] > 0 {
+		union = append(union, "string")
+	}
+
+	if n := s.Types["number"]; n > 0 {
+		if s.Integers == n {
+			union = append(union, "long")
+		} else {
+			union = append(union, "double")
+		}
+	}
+
+	if s.Types["true"] > 0 || s.Types["false"] > 0 {
+		union = append(union, "boolean")
+	}
+
+	switch len(union) {
+	case 0:
+		return "null"
+	case 1:
+		return union[0]
+	}
+
+	return union
+}
+
+// Builds a record for the objects at a location, or a map when none of their keys could name a field
+func (g *avroGen) object(s *Shape, name string) interface{} {
+	if s.keyedByValue() {
+		return avroMap{Type: "map", Values: g.schema(s.memberValues(), name+"Value")}
+	}
+
+	fields := make(map[string]bool)
+
+	rec := avroRecord{Type: "record", Name: uniqueName(name, g.used), Fields: make([]avroField, 0, len(s.Keys))}
+
+	for _, k := range s.Keys {
+		var (
+			f     = s.Fields[k]
+			field = avroField{Name: uniqueName(identifierFor(k), fields), Type: g.schema(f, rec.Name+exportedName(k))}
+		)
+
+		if field.Name != k {
+			field.Doc = "JSON key " + strconv.Quote(k)
+		}
+
+		if !s.Required(k) && !f.Nullable() {
+			field.Type = prependNull(field.Type)
+		}
+
+		if union, ok := field.Type.([]interface{}); field.Type == "null" || ok && union[0] == "null" {
+			field.Default = json.RawMessage("null")
+		}
+
+		rec.Fields = append(rec.Fields, field)
+	}
+
+	return rec
+}
+
+// Makes a schema nullable, placing null first so that it can be the default
+func prependNull(schema interface{}) interface{} {
+	if union, ok := schema.([]interface{}); ok {
+		return append([]interface{}{"null"}, union...)
+	}
+
+	if schema == "null" {
+		return schema
+	}
+
+	return []interface{}{"null", schema}
+}
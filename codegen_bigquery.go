@@ -0,0 +1,93 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// One column of a BigQuery table schema, as bq and the API write it
+type bigQueryField struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Mode        string           `json:"mode"`
+	Description string           `json:"description,omitempty"`
+	Fields      []*bigQueryField `json:"fields,omitempty"`
+}
+
+// BigQuery's types for each kind of scalar; anything else is stored as JSON
+var bigQueryTypes = map[valueKind]string{
+	kindString:  "STRING",
+	kindInteger: "INTEGER",
+	kindNumber:  "FLOAT",
+	kindBoolean: "BOOLEAN",
+	kindUnknown: "STRING",
+}
+
+// Generates a BigQuery table schema, as JSON, with a column for every key of the documents the shape was inferred from, which must be objects
+//
+// Nested objects become RECORD columns and arrays REPEATED ones. Columns are REQUIRED when every object had the key and it was never null, and NULLABLE otherwise. Arrays of arrays, objects keyed by data rather than field names, empty objects, and keys whose values were of several types become JSON columns, and keys only ever null become STRING. Keys BigQuery cannot use as column names are renamed with underscores, noting the JSON key in the column's description.
+func (s *Shape) BigQuerySchema() ([]byte, error) {
+	if s.kind() != kindObject || s.keyedByValue() {
+		return nil, fmt.Errorf("documents are not objects with named members")
+	}
+
+	return json.MarshalIndent(bigQueryFields(s), "", "  ")
+}
+
+// Builds a column for each member of the objects at a location
+func bigQueryFields(s *Shape) []*bigQueryField {
+	var (
+		fields = make([]*bigQueryField, 0, len(s.Keys))
+		// BigQuery column names ignore case
+		used = make(map[string]bool)
+	)
+
+	for _, k := range s.Keys {
+		var (
+			f     = s.Fields[k]
+			name  = identifierFor(k)
+			field = &bigQueryField{Mode: "NULLABLE"}
+		)
+
+		field.Name = name
+		for i := 2; used[strings.ToLower(field.Name)]; i++ {
+			field.Name = name + "_" + strconv.Itoa(i)
+		}
+		used[strings.ToLower(field.Name)] = true
+
+		if field.Name != k {
+			field.Description = "JSON key " + strconv.Quote(k)
+		}
+
+		if s.Required(k) && !f.Nullable() {
+			field.Mode = "REQUIRED"
+		}
+
+		if f.kind() == kindArray && f.Items != nil && f.Items.kind() != kindArray {
+			field.Mode = "REPEATED"
+			f = f.Items
+		}
+
+		field.Type, field.Fields = bigQueryType(f)
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Decides the column type of the values at a location, with the columns of a RECORD
+func bigQueryType(s *Shape) (string, []*bigQueryField) {
+	kind := s.kind()
+
+	if kind == kindObject && len(s.Keys) > 0 && !s.keyedByValue() {
+		return "RECORD", bigQueryFields(s)
+	}
+
+	if typ, ok := bigQueryTypes[kind]; ok {
+		return typ, nil
+	}
+
+	return "JSON", nil
+}
@@ -0,0 +1,91 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Strings longer than this are analyzed as text, and not indexed whole by the keyword subfield
+const elasticKeywordLength = 256
+
+// Generates an Elasticsearch index mapping, as JSON, with a property for every key of the documents the shape was inferred from, which must be objects
+//
+// Strings that were all dates or timestamps map to date. Other strings map to keyword, unless most held whitespace or one ran past 256 bytes, in which case they map to text with a keyword subfield. Numbers that were always integers map to long, other numbers double. Arrays of objects map to nested, so each element is queried on its own, and objects keyed by data rather than field names, such as by date or ID, map to flattened. Keys whose values were of several types are kept in _source but not indexed, and keys only ever null or empty are left for dynamic mapping.
+func (s *Shape) ElasticsearchMapping() ([]byte, error) {
+	var out bytes.Buffer
+
+	if s.kind() != kindObject || s.keyedByValue() {
+		return nil, fmt.Errorf("documents are not objects with named members")
+	}
+
+	mapping := marshalMembers([]member{
+		{Key: "mappings", Value: elasticProperties(s)},
+	})
+
+	if err := json.Indent(&out, mapping, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Builds {"properties": ...} with the mapping of each member of the objects at a location
+func elasticProperties(s *Shape) json.RawMessage {
+	props := make([]member, 0, len(s.Keys))
+
+	for _, k := range s.Keys {
+		if m := elasticMapping(s.Fields[k]); m != nil {
+			props = append(props, member{Key: k, Value: m})
+		}
+	}
+
+	return marshalMembers([]member{{Key: "properties", Value: marshalMembers(props)}})
+}
+
+// Builds the mapping for the values at a location, or nil to leave them to dynamic mapping
+func elasticMapping(s *Shape) json.RawMessage {
+	nested := false
+
+	// Elasticsearch indexes array elements as though each were the value itself
+	for s.kind() == kindArray {
+		if s.Items == nil {
+			return nil
+		}
+		if s.Items.kind() == kindObject {
+			nested = true
+		}
+		s = s.Items
+	}
+
+	switch s.kind() {
+	case kindObject:
+		if s.keyedByValue() {
+			return json.RawMessage(`{"type":"flattened"}`)
+		}
+		props := elasticProperties(s)
+		if nested {
+			return append(json.RawMessage(`{"type":"nested",`), props[1:]...)
+		}
+		return props
+	case kindString:
+		count := s.Types["string"]
+		switch {
+		case s.Formats[FormatDate.String()]+s.Formats[FormatDateTime.String()] == count:
+			return json.RawMessage(`{"type":"date"}`)
+		case s.Spaced*2 > count || s.MaxLength > elasticKeywordLength:
+			return json.RawMessage(fmt.Sprintf(`{"type":"text","fields":{"keyword":{"type":"keyword","ignore_above":%d}}}`, elasticKeywordLength))
+		}
+		return json.RawMessage(`{"type":"keyword"}`)
+	case kindInteger:
+		return json.RawMessage(`{"type":"long"}`)
+	case kindNumber:
+		return json.RawMessage(`{"type":"double"}`)
+	case kindBoolean:
+		return json.RawMessage(`{"type":"boolean"}`)
+	case kindMixed:
+		return json.RawMessage(`{"type":"object","enabled":false}`)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// Collects the declarations of generated Go types
+type goGen struct {
+	decls     []string
+	used      map[string]bool
+	needsJson bool
+}
+
+// Generates Go type declarations with json tags that can hold every document the shape was inferred from, led by a type called name
+//
+// Each object becomes a struct type named after its path, e.g. Order and OrderCustomer. Members some objects lack are tagged omitempty, and they and nullable members are pointers unless they are slices. Arrays become slices of their merged element type. Numbers that were always integers become int64, other numbers float64, and values of mixed type, or only ever null, json.RawMessage. Honors WithPackageName.
+func (s *Shape) GoTypes(name string, opts ...Option) ([]byte, error) {
+	var (
+		cfg = newConfig(opts)
+		gen = &goGen{used: make(map[string]bool)}
+		pkg = cfg.packageName
+		sb  strings.Builder
+	)
+
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	name = exportedName(name)
+
+	if s.kind() == kindObject {
+		gen.structType(s, name)
+	} else {
+		// The named type leads, ahead of any struct its elements need
+		gen.used[name] = true
+		gen.decls = append(gen.decls, "")
+		gen.decls[0] = fmt.Sprintf("type %s %s\n", name, gen.typeExpr(s, name))
+	}
+
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+
+	if gen.needsJson {
+		sb.WriteString("import \"encoding/json\"\n\n")
+	}
+
+	sb.WriteString(strings.Join(gen.decls, "\n"))
+
+	return format.Source([]byte(sb.String()))
+}
+
+// Declares a struct type for an object shape under a name not yet used, returning that name
+func (g *goGen) structType(s *Shape, name string) string {
+	var (
+		sb     strings.Builder
+		fields = make(map[string]bool)
+	)
+
+	name = uniqueName(name, g.used)
+
+	// Reserve the position so the struct precedes the types of its members
+	at := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	fmt.Fprintf(&sb, "type %s struct {\n", name)
+
+	for _, k := range s.Keys {
+		f := s.Fields[k]
+
+		if !validTagName(k) {
+			fmt.Fprintf(&sb, "\t// Key %s cannot be named in a json tag, so it is left out\n", strconv.Quote(k))
+			continue
+		}
+
+		var (
+			field    = uniqueName(exportedName(k), fields)
+			optional = !s.Required(k)
+			expr     = g.typeExpr(f, name+field)
+			tag      = k
+		)
+
+		if (optional || f.Nullable()) && !strings.HasPrefix(expr, "[]") && expr != "json.RawMessage" {
+			expr = "*" + expr
+		}
+
+		if optional {
+			tag += ",omitempty"
+		}
+
+		fmt.Fprintf(&sb, "\t%s %s `json:%s`\n", field, expr, strconv.Quote(tag))
+	}
+
+	sb.WriteString("}\n")
+	g.decls[at] = sb.String()
+
+	return name
+}
+
+// Writes the Go type for the values at a location, declaring struct types as needed; name is used for a struct
+func (g *goGen) typeExpr(s *Shape, name string) string {
+	switch s.kind() {
+	case kindObject:
+		return g.structType(s, name)
+	case kindArray:
+		if s.Items == nil {
+			g.needsJson = true
+			return "[]json.RawMessage"
+		}
+		elem := g.typeExpr(s.Items, name+"Item")
+		if s.Items.Nullable() && !strings.HasPrefix(elem, "[]") && elem != "json.RawMessage" {
+			elem = "*" + elem
+		}
+		return "[]" + elem
+	case kindString:
+		return "string"
+	case kindInteger:
+		return "int64"
+	case kindNumber:
+		return "float64"
+	case kindBoolean:
+		return "bool"
+	}
+
+	g.needsJson = true
+	return "json.RawMessage"
+}
+
+// Reports whether encoding/json accepts key as the name in a struct tag
+func validTagName(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, c := range key {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case c > 0x7f || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Collects the component schemas of a generated OpenAPI fragment
+type openAPIGen struct {
+	schemas []member
+	used    map[string]bool
+}
+
+// Generates an OpenAPI 3.1 components fragment, as JSON, with schemas that validate every document the shape was inferred from, led by one called name
+//
+// Each object becomes a component schema named after its path, as GoTypes names structs, listing as required the keys every object had and referenced wherever it appears. Objects keyed by data rather than field names, such as by date or ID, become additionalProperties. A location that held values of several types lists them all, or combines a reference with them using anyOf, and each string, number, and boolean carries the first value seen as an example.
+func (s *Shape) OpenAPISchemas(name string) ([]byte, error) {
+	var (
+		gen = &openAPIGen{used: make(map[string]bool)}
+		out bytes.Buffer
+	)
+
+	name = exportedName(name)
+
+	if s.kind() == kindObject && !s.keyedByValue() {
+		gen.component(s, name)
+	} else {
+		// The named schema leads, ahead of any component its values need
+		gen.used[name] = true
+		gen.schemas = append(gen.schemas, member{Key: name})
+		gen.schemas[0].Value = gen.schema(s, name)
+	}
+
+	fragment := marshalMembers([]member{
+		{Key: "components", Value: marshalMembers([]member{
+			{Key: "schemas", Value: marshalMembers(gen.schemas)},
+		})},
+	})
+
+	if err := json.Indent(&out, fragment, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Declares a component schema for the objects at a location under a name not yet used, returning that name
+func (g *openAPIGen) component(s *Shape, name string) string {
+	var (
+		props    = make([]member, 0, len(s.Keys))
+		required = s.RequiredKeys()
+		schema   = []member{{Key: "type", Value: json.RawMessage(`"object"`)}}
+	)
+
+	name = uniqueName(name, g.used)
+
+	// Reserve the position so the component precedes those of its members
+	at := len(g.schemas)
+	g.schemas = append(g.schemas, member{Key: name})
+
+	for _, k := range s.Keys {
+		props = append(props, member{Key: k, Value: g.schema(s.Fields[k], name+exportedName(k))})
+	}
+
+	schema = append(schema, member{Key: "properties", Value: marshalMembers(props)})
+
+	if len(required) > 0 {
+		list, _ := json.Marshal(required)
+		schema = append(schema, member{Key: "required", Value: list})
+	}
+
+	g.schemas[at].Value = marshalMembers(schema)
+
+	return name
+}
+
+// Builds the schema for the values at a location, declaring components as needed; name is used for a component
+func (g *openAPIGen) schema(s *Shape, name string) json.RawMessage {
+	var (
+		types  []string
+		schema []member
+		ref    json.RawMessage
+	)
+
+	if s.Types["object"] > 0 {
+		if s.keyedByValue() {
+			types = append(types, "object")
+			schema = append(schema, member{Key: "additionalProperties", Value: g.schema(s.memberValues(), name+"Value")})
+		} else {
+			ref = marshalMembers([]member{{Key: "$ref", Value: marshalValue("#/components/schemas/" + g.component(s, name))}})
+		}
+	}
+
+	if s.Types["array"] > 0 {
+		types = append(types, "array")
+		if s.Items != nil {
+			schema = append(schema, member{Key: "items", Value: g.schema(s.Items, name+"Item")})
+		}
+	}
+
+	if s.Types["string"] > 0 {
+		types = append(types, "string")
+	}
+
+	if n := s.Types["number"]; n > 0 {
+		if s.Integers == n {
+			types = append(types, "integer")
+		} else {
+			types = append(types, "number")
+		}
+	}
+
+	if s.Types["true"] > 0 || s.Types["false"] > 0 {
+		types = append(types, "boolean")
+	}
+
+	if s.Nullable() {
+		types = append(types, "null")
+	}
+
+	if s.Example != nil {
+		schema = append(schema, member{Key: "examples", Value: json.RawMessage("[" + string(s.Example) + "]")})
+	}
+
+	switch len(types) {
+	case 0:
+	case 1:
+		schema = append([]member{{Key: "type", Value: marshalValue(types[0])}}, schema...)
+	default:
+		schema = append([]member{{Key: "type", Value: marshalValue(types)}}, schema...)
+	}
+
+	switch {
+	case ref == nil:
+		return marshalMembers(schema)
+	case len(types) == 0:
+		return ref
+	}
+
+	return marshalMembers([]member{{Key: "anyOf", Value: json.RawMessage("[" + string(ref) + "," + string(marshalMembers(schema)) + "]")}})
+}
+
+// Marshals a string or list of strings, which cannot fail
+func marshalValue(v interface{}) json.RawMessage {
+	out, _ := json.Marshal(v)
+	return out
+}
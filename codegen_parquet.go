@@ -0,0 +1,106 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Generates a Parquet message schema, in the text form parquet-mr parses and parquet-tools prints, with a column for every key of the documents the shape was inferred from, which must be objects
+//
+// Columns are required when every object had the key and it was never null, and optional otherwise. Strings map to UTF-8 binary, or to DATE and TIMESTAMP when they were all dates or all timestamps; numbers that were always integers map to int64, other numbers double. Nested objects become groups, arrays LIST groups, and objects keyed by data rather than field names, such as by date or ID, MAP groups. Keys whose values were of several types hold JSON. Keys Parquet tools cannot parse as column names are renamed with underscores.
+func (s *Shape) ParquetSchema(name string) ([]byte, error) {
+	var sb strings.Builder
+
+	if s.kind() != kindObject || s.keyedByValue() {
+		return nil, fmt.Errorf("documents are not objects with named members")
+	}
+
+	fmt.Fprintf(&sb, "message %s {\n", identifierFor(name))
+	parquetFields(&sb, s, 1)
+	sb.WriteString("}\n")
+
+	return []byte(sb.String()), nil
+}
+
+// Writes a column for each member of the objects at a location, indented depth levels
+func parquetFields(sb *strings.Builder, s *Shape, depth int) {
+	used := make(map[string]bool)
+
+	for _, k := range s.Keys {
+		var (
+			f          = s.Fields[k]
+			repetition = "optional"
+		)
+
+		if s.Required(k) && !f.Nullable() {
+			repetition = "required"
+		}
+
+		parquetColumn(sb, f, repetition, uniqueName(identifierFor(k), used), depth)
+	}
+}
+
+// Writes the column for the values at a location
+func parquetColumn(sb *strings.Builder, s *Shape, repetition, name string, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	switch s.kind() {
+	case kindObject:
+		if s.keyedByValue() {
+			fmt.Fprintf(sb, "%s%s group %s (MAP) {\n", indent, repetition, name)
+			fmt.Fprintf(sb, "%s  repeated group key_value {\n", indent)
+			fmt.Fprintf(sb, "%s    required binary key (STRING);\n", indent)
+			values := s.memberValues()
+			parquetColumn(sb, values, elementRepetition(values), "value", depth+2)
+			fmt.Fprintf(sb, "%s  }\n%s}\n", indent, indent)
+			return
+		}
+		fmt.Fprintf(sb, "%s%s group %s {\n", indent, repetition, name)
+		parquetFields(sb, s, depth+1)
+		fmt.Fprintf(sb, "%s}\n", indent)
+		return
+	case kindArray:
+		if s.Items == nil {
+			break
+		}
+		fmt.Fprintf(sb, "%s%s group %s (LIST) {\n", indent, repetition, name)
+		fmt.Fprintf(sb, "%s  repeated group list {\n", indent)
+		parquetColumn(sb, s.Items, elementRepetition(s.Items), "element", depth+2)
+		fmt.Fprintf(sb, "%s  }\n%s}\n", indent, indent)
+		return
+	}
+
+	physical, logical := parquetPrimitive(s)
+	fmt.Fprintf(sb, "%s%s %s %s%s;\n", indent, repetition, physical, name, logical)
+}
+
+// Element and map values are required unless one was null
+func elementRepetition(s *Shape) string {
+	if s.Nullable() {
+		return "optional"
+	}
+
+	return "required"
+}
+
+// Names the physical type of the scalars at a location, with any logical type written as it follows the column name, e.g. "binary" and " (STRING)"; other values are JSON
+func parquetPrimitive(s *Shape) (string, string) {
+	switch s.kind() {
+	case kindString:
+		switch s.Types["string"] {
+		case s.Formats[FormatDate.String()]:
+			return "int32", " (DATE)"
+		case s.Formats[FormatDateTime.String()]:
+			return "int64", " (TIMESTAMP(MICROS,true))"
+		}
+		return "binary", " (STRING)"
+	case kindInteger:
+		return "int64", ""
+	case kindNumber:
+		return "double", ""
+	case kindBoolean:
+		return "boolean", ""
+	}
+
+	return "binary", " (JSON)"
+}
@@ -0,0 +1,208 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Collects the messages of a generated .proto file
+type protoGen struct {
+	decls     []string
+	used      map[string]bool
+	needsJson bool
+}
+
+// The protobuf type of a field: its type name, whether the field repeats it, and whether it is a scalar, which needs optional for presence
+type protoType struct {
+	name     string
+	repeated bool
+	scalar   bool
+}
+
+// Generates a proto3 file with messages that can hold every document the shape was inferred from, led by a message called name
+//
+// Each object becomes a message named after its path, as GoTypes names structs, with fields numbered in the order their keys were first seen, so numbers stay put as samples that only add keys are aggregated. Field names are the keys in snake_case, with json_name set wherever protobuf's JSON mapping would not give back the key. Members some objects lack, and nullable members, are optional; arrays are repeated, with arrays of arrays wrapped in a message; objects keyed by data rather than field names, such as by date or ID, become maps; and values of mixed type, or only ever null, are google.protobuf.Value. A document that is not an object is held in a field called value. Honors WithPackageName.
+func (s *Shape) ProtoMessages(name string, opts ...Option) []byte {
+	var (
+		cfg = newConfig(opts)
+		gen = &protoGen{used: make(map[string]bool)}
+		sb  strings.Builder
+	)
+
+	name = exportedName(name)
+
+	if s.kind() == kindObject && !s.keyedByValue() {
+		gen.message(s, name)
+	} else {
+		// The named message leads, ahead of any its value needs
+		gen.used[name] = true
+		gen.decls = append(gen.decls, "")
+		gen.decls[0] = protoWrapper(name, "value", gen.fieldType(s, name+"Value"))
+	}
+
+	sb.WriteString("syntax = \"proto3\";\n\n")
+
+	if cfg.packageName != "" {
+		fmt.Fprintf(&sb, "package %s;\n\n", cfg.packageName)
+	}
+
+	if gen.needsJson {
+		sb.WriteString("import \"google/protobuf/struct.proto\";\n\n")
+	}
+
+	sb.WriteString(strings.Join(gen.decls, "\n"))
+
+	return []byte(sb.String())
+}
+
+// Declares a message for the objects at a location under a name not yet used, returning that name
+func (g *protoGen) message(s *Shape, name string) string {
+	var (
+		sb     strings.Builder
+		fields = make(map[string]bool)
+	)
+
+	name = uniqueName(name, g.used)
+
+	// Reserve the position so the message precedes the types of its fields
+	at := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	fmt.Fprintf(&sb, "message %s {\n", name)
+
+	for i, k := range s.Keys {
+		var (
+			f     = s.Fields[k]
+			field = uniqueName(protoFieldName(k), fields)
+			typ   = g.fieldType(f, name+exportedName(k))
+			label string
+			opts  string
+		)
+
+		switch {
+		case typ.repeated:
+			label = "repeated "
+		case typ.scalar && (!s.Required(k) || f.Nullable()):
+			label = "optional "
+		}
+
+		if protoJsonName(field) != k {
+			opts = fmt.Sprintf(" [json_name = %s]", strconv.Quote(k))
+		}
+
+		fmt.Fprintf(&sb, "  %s%s %s = %d%s;\n", label, typ.name, field, i+1, opts)
+	}
+
+	sb.WriteString("}\n")
+	g.decls[at] = sb.String()
+
+	return name
+}
+
+// Declares a message holding typ in a single field, for values protobuf cannot nest directly, returning its name
+func (g *protoGen) wrapper(name, field string, typ protoType) string {
+	name = uniqueName(name, g.used)
+	g.decls = append(g.decls, protoWrapper(name, field, typ))
+
+	return name
+}
+
+func protoWrapper(name, field string, typ protoType) string {
+	label := ""
+	if typ.repeated {
+		label = "repeated "
+	}
+
+	return fmt.Sprintf("message %s {\n  %s%s %s = 1;\n}\n", name, label, typ.name, field)
+}
+
+// Decides the protobuf type of the values at a location, declaring messages as needed; name is used for a message
+func (g *protoGen) fieldType(s *Shape, name string) protoType {
+	switch s.kind() {
+	case kindObject:
+		if !s.keyedByValue() {
+			return protoType{name: g.message(s, name)}
+		}
+		values := g.fieldType(s.memberValues(), name+"Value")
+		if values.repeated {
+			values = protoType{name: g.wrapper(name+"Value", "values", values)}
+		}
+		return protoType{name: "map<string, " + values.name + ">"}
+	case kindArray:
+		if s.Items == nil {
+			g.needsJson = true
+			return protoType{name: "google.protobuf.Value", repeated: true}
+		}
+		items := g.fieldType(s.Items, name+"Item")
+		if items.repeated {
+			items = protoType{name: g.wrapper(name+"Item", "values", items)}
+		}
+		return protoType{name: items.name, repeated: true}
+	case kindString:
+		return protoType{name: "string", scalar: true}
+	case kindInteger:
+		return protoType{name: "int64", scalar: true}
+	case kindNumber:
+		return protoType{name: "double", scalar: true}
+	case kindBoolean:
+		return protoType{name: "bool", scalar: true}
+	}
+
+	g.needsJson = true
+	return protoType{name: "google.protobuf.Value"}
+}
+
+// Turns a key into a snake_case field name, e.g. "userId" into "user_id" and "2fa" into "field_2fa"
+func protoFieldName(key string) string {
+	var (
+		sb   strings.Builder
+		prev rune
+	)
+
+	for _, r := range key {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		} else if sb.Len() > 0 && prev != '_' {
+			sb.WriteByte('_')
+			r = '_'
+		}
+		prev = r
+	}
+
+	name := strings.TrimRight(sb.String(), "_")
+
+	if name == "" {
+		name = "field"
+	} else if !unicode.IsLetter(rune(name[0])) {
+		name = "field_" + name
+	}
+
+	return name
+}
+
+// Derives the JSON name protobuf gives a field by default: underscores dropped and the letter after each capitalized
+func protoJsonName(field string) string {
+	var (
+		sb    strings.Builder
+		upper bool
+	)
+
+	for _, r := range field {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The SQL flavor CreateTable writes
+type SQLDialect int
+
+const (
+	Postgres SQLDialect = iota
+	MySQL
+	SQLite
+)
+
+// Column types by dialect, for each kind of value; containers and mixed values are stored as JSON
+var sqlTypes = map[SQLDialect]map[valueKind]string{
+	Postgres: {kindString: "text", kindInteger: "bigint", kindNumber: "double precision", kindBoolean: "boolean", kindUnknown: "text", kindMixed: "jsonb"},
+	MySQL:    {kindString: "TEXT", kindInteger: "BIGINT", kindNumber: "DOUBLE", kindBoolean: "BOOLEAN", kindUnknown: "TEXT", kindMixed: "JSON"},
+	SQLite:   {kindString: "TEXT", kindInteger: "INTEGER", kindNumber: "REAL", kindBoolean: "INTEGER", kindUnknown: "TEXT", kindMixed: "TEXT"},
+}
+
+// Quotes an identifier for the dialect, doubling any quote within it
+func (d SQLDialect) quote(name string) string {
+	q := `"`
+	if d == MySQL {
+		q = "`"
+	}
+
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
+
+// Generates a CREATE TABLE statement with a column for every key of the objects in the array, typed by the values they held
+//
+// Columns follow the order keys were first seen, and are NOT NULL when every object had the key and it was never null. Nested objects and arrays, and keys whose values were of several types, become JSON columns; keys only ever null become text. Every element must be an object. Honors WithDialect.
+func (a *RawArray) CreateTable(table string, opts ...Option) (string, error) {
+	var (
+		cfg   = newConfig(opts)
+		types = sqlTypes[cfg.dialect]
+		agg   = NewAggregator()
+		sb    strings.Builder
+	)
+
+	if types == nil {
+		return "", fmt.Errorf("unknown sql dialect %d", cfg.dialect)
+	}
+
+	for i, elem := range *a {
+		if typ, err := JsonTypeOf(elem); err != nil || typ != Object {
+			return "", fmt.Errorf("element %d is not an object", i)
+		}
+		if err := agg.Add(elem); err != nil {
+			return "", fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	var (
+		s       = agg.Shape()
+		columns = make([]string, 0, len(s.Keys))
+	)
+
+	for _, k := range s.Keys {
+		f := s.Fields[k]
+
+		kind := f.kind()
+		if kind == kindObject || kind == kindArray {
+			kind = kindMixed
+		}
+
+		column := cfg.dialect.quote(k) + " " + types[kind]
+		if s.Required(k) && !f.Nullable() {
+			column += " NOT NULL"
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return "", fmt.Errorf("no keys to make columns of")
+	}
+
+	fmt.Fprintf(&sb, "CREATE TABLE %s (\n  %s\n);\n", cfg.dialect.quote(table), strings.Join(columns, ",\n  "))
+
+	return sb.String(), nil
+}
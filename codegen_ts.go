@@ -0,0 +1,109 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Collects the declarations of generated TypeScript types
+type tsGen struct {
+	decls []string
+	used  map[string]bool
+}
+
+// Property names TypeScript accepts without quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Generates TypeScript interfaces that can hold every document the shape was inferred from, led by a type called name
+//
+// Each object becomes an exported interface named after its path, as GoTypes names structs. Members some objects lack are optional, and a location that held values of several types gets a union of them, e.g. string | number | null.
+func (s *Shape) TypeScriptTypes(name string) []byte {
+	gen := &tsGen{used: make(map[string]bool)}
+	name = exportedName(name)
+
+	if s.kind() == kindObject && !s.Nullable() {
+		gen.interfaceType(s, name)
+	} else {
+		// The named type leads, ahead of any interface its values need
+		gen.used[name] = true
+		gen.decls = append(gen.decls, "")
+		gen.decls[0] = fmt.Sprintf("export type %s = %s;\n", name, gen.typeExpr(s, name))
+	}
+
+	return []byte(strings.Join(gen.decls, "\n"))
+}
+
+// Declares an interface for the objects at a location under a name not yet used, returning that name
+func (g *tsGen) interfaceType(s *Shape, name string) string {
+	var sb strings.Builder
+
+	name = uniqueName(name, g.used)
+
+	// Reserve the position so the interface precedes the types of its members
+	at := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	fmt.Fprintf(&sb, "export interface %s {\n", name)
+
+	for _, k := range s.Keys {
+		prop := k
+		if !tsIdentifier.MatchString(k) {
+			prop = strconv.Quote(k)
+		}
+
+		if !s.Required(k) {
+			prop += "?"
+		}
+
+		fmt.Fprintf(&sb, "  %s: %s;\n", prop, g.typeExpr(s.Fields[k], name+exportedName(k)))
+	}
+
+	sb.WriteString("}\n")
+	g.decls[at] = sb.String()
+
+	return name
+}
+
+// Writes the union of the types of the values at a location, declaring interfaces as needed; name is used for an interface
+func (g *tsGen) typeExpr(s *Shape, name string) string {
+	var union []string
+
+	if s.Types["object"] > 0 {
+		union = append(union, g.interfaceType(s, name))
+	}
+
+	if s.Types["array"] > 0 {
+		elem := "unknown"
+		if s.Items != nil {
+			elem = g.typeExpr(s.Items, name+"Item")
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		union = append(union, elem+"[]")
+	}
+
+	if s.Types["string"] > 0 {
+		union = append(union, "string")
+	}
+
+	if s.Types["number"] > 0 {
+		union = append(union, "number")
+	}
+
+	if s.Types["true"] > 0 || s.Types["false"] > 0 {
+		union = append(union, "boolean")
+	}
+
+	if s.Nullable() {
+		union = append(union, "null")
+	}
+
+	if len(union) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(union, " | ")
+}
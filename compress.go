@@ -0,0 +1,56 @@
+package jsondescriber
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// Under WithDecompression, wraps r in a gzip or zlib reader when its first bytes carry that format's header, and otherwise returns a reader of the same input
+//
+// A gzip header cannot begin JSON, but a zlib header can look like the start of a number, such as "80", so input is taken for zlib only if its header is well formed, asks for no preset dictionary, and the start of the stream decompresses.
+func (c *config) decompress(r io.Reader) (io.Reader, error) {
+	if !c.decompression {
+		return r, nil
+	}
+
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(2)
+
+	switch {
+	case len(head) == 2 && head[0] == 0x1f && head[1] == 0x8b:
+		return gzip.NewReader(br)
+	case len(head) == 2 && isZlib(head) && probeZlib(br):
+		return zlib.NewReader(br)
+	}
+
+	return br, nil
+}
+
+// Reports whether two bytes make a zlib header: deflate with a window of at most 32KB, no preset dictionary, and a valid check value
+func isZlib(head []byte) bool {
+	var (
+		method = head[0] & 0x0f
+		window = head[0] >> 4
+		dict   = head[1]&0x20 != 0
+	)
+
+	return method == 8 && window <= 7 && !dict && (uint(head[0])<<8|uint(head[1]))%31 == 0
+}
+
+// Reports whether the buffered start of the input decompresses as zlib, without consuming it
+func probeZlib(br *bufio.Reader) bool {
+	peeked, _ := br.Peek(br.Size())
+
+	zr, err := zlib.NewReader(bytes.NewReader(peeked))
+	if err != nil {
+		return false
+	}
+
+	_, err = zr.Read(make([]byte, 1))
+
+	// A stream longer than the buffer may need more of it before giving up its first byte
+	return err == nil || err == io.EOF || err == io.ErrUnexpectedEOF && len(peeked) == br.Size()
+}
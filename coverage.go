@@ -0,0 +1,239 @@
+package jsondescriber
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// How a struct type and a document line up, as found by Coverage
+type CoverageReport struct {
+	// Paths of document members that no struct field would decode, which json.Unmarshal silently drops
+	Unmapped []string
+	// Paths of struct fields, by their JSON names, that no member of the document would fill
+	Missing []string
+}
+
+// Reports which members of a document would be dropped when decoding it into a struct type, and which fields of the struct it would leave unset
+//
+// structType is a struct value, a pointer to one, or its reflect.Type. Keys are matched to fields as json.Unmarshal matches them: by json tag or field name, exactly or else ignoring case, with fields of embedded structs promoted. Nested structs, and the elements of slices, arrays, and maps of them, are checked in turn, with array indices and map keys written as wildcards. Values bound for interface{} fields, json.RawMessage, or a type with its own UnmarshalJSON or UnmarshalText count as fully covered. A field is missing only if none of the objects decoded into its struct had it. Honors WithPathStyle.
+func Coverage(structType interface{}, data []byte, opts ...Option) (*CoverageReport, error) {
+	var (
+		cfg = newConfig(opts)
+		t   reflect.Type
+	)
+
+	if rt, ok := structType.(reflect.Type); ok {
+		t = rt
+	} else {
+		t = reflect.TypeOf(structType)
+	}
+
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("coverage needs a struct type, not %v", t)
+	}
+
+	typ, err := JsonTypeOf(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != Object {
+		return nil, &ErrUnexpectedType{Want: Object, Got: typ}
+	}
+
+	w := &coverageWalk{cfg: cfg, unmapped: make(map[string]bool), fields: make(map[string]bool)}
+
+	if err = w.walk(t, data, ""); err != nil {
+		return nil, err
+	}
+
+	report := &CoverageReport{Unmapped: sortedKeys(w.unmapped), Missing: make([]string, 0)}
+
+	for _, path := range sortedKeys(w.fields) {
+		if !w.fields[path] {
+			report.Missing = append(report.Missing, path)
+		}
+	}
+
+	return report, nil
+}
+
+// The state of one Coverage check
+type coverageWalk struct {
+	cfg      *config
+	unmapped map[string]bool
+	// Every field path seen, and whether any document member filled it
+	fields map[string]bool
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Checks the value raw at path against type t
+func (w *coverageWalk) walk(t reflect.Type, raw json.RawMessage, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(unmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	typ, _ := JsonTypeOf(raw)
+
+	switch {
+	case t.Kind() == reflect.Struct && typ == Object:
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+
+		fields := structFields(t)
+
+		for _, f := range fields {
+			child := joinKey(w.cfg.pathStyle, path, f.name)
+			if _, ok := w.fields[child]; !ok {
+				w.fields[child] = false
+			}
+		}
+
+		for _, m := range members {
+			child := joinKey(w.cfg.pathStyle, path, m.Key)
+
+			f, ok := matchField(fields, m.Key)
+			if !ok {
+				w.unmapped[child] = true
+				continue
+			}
+
+			child = joinKey(w.cfg.pathStyle, path, f.name)
+			w.fields[child] = true
+
+			if err = w.walk(f.typ, m.Value, child); err != nil {
+				return err
+			}
+		}
+
+	// A []byte is read from a base64 string, not an array
+	case (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 || t.Kind() == reflect.Array) && typ == Array:
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+
+		for _, elem := range arr {
+			if err := w.walk(t.Elem(), elem, joinWildcard(w.cfg.pathStyle, path)); err != nil {
+				return err
+			}
+		}
+
+	case t.Kind() == reflect.Map && typ == Object:
+		obj := make(RawObject)
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+
+		for _, k := range sortedKeys(obj) {
+			if err := w.walk(t.Elem(), obj[k], joinWildcard(w.cfg.pathStyle, path)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// A struct field as json.Unmarshal sees it
+type structField struct {
+	name string
+	typ  reflect.Type
+}
+
+// Lists the fields json.Unmarshal would decode into, by JSON name, promoting those of untagged embedded structs; a shallower field hides a deeper one of the same name
+func structFields(t reflect.Type) []structField {
+	var (
+		fields = make([]structField, 0)
+		taken  = make(map[string]bool)
+		level  = []reflect.Type{t}
+		seen   = map[reflect.Type]bool{t: true}
+	)
+
+	for len(level) > 0 {
+		var (
+			next  []reflect.Type
+			found = make(map[string]bool)
+		)
+
+		for _, st := range level {
+			for i := 0; i < st.NumField(); i++ {
+				sf := st.Field(i)
+				tag := sf.Tag.Get("json")
+
+				if tag == "-" {
+					continue
+				}
+
+				name, _, _ := strings.Cut(tag, ",")
+				ft := sf.Type
+				for ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					if !seen[ft] {
+						seen[ft] = true
+						next = append(next, ft)
+					}
+					continue
+				}
+
+				if !sf.IsExported() {
+					continue
+				}
+
+				if name == "" {
+					name = sf.Name
+				}
+
+				if !taken[name] && !found[name] {
+					found[name] = true
+					fields = append(fields, structField{name: name, typ: sf.Type})
+				}
+			}
+		}
+
+		for name := range found {
+			taken[name] = true
+		}
+
+		level = next
+	}
+
+	return fields
+}
+
+// Finds the field a key decodes into: the one with exactly that name, or else the first whose name matches ignoring case
+func matchField(fields []structField, key string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+
+	return structField{}, false
+}
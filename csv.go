@@ -0,0 +1,204 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Writes the array's objects to w as CSV, headed by every key any of them had, in the order keys were first seen
+//
+// Strings are written decoded, numbers and booleans as written in the JSON, and nulls and missing keys as empty cells. Nested objects and arrays are written as compact JSON. Every element must be an object. Honors WithDelimiter.
+func (a *RawArray) ToCSV(w io.Writer, opts ...Option) error {
+	var (
+		cfg     = newConfig(opts)
+		out     = csv.NewWriter(w)
+		columns = make([]string, 0)
+		index   = make(map[string]int)
+		rows    = make([][]member, len(*a))
+	)
+
+	if cfg.delimiter != 0 {
+		out.Comma = cfg.delimiter
+	}
+
+	for i, elem := range *a {
+		members, err := orderedMembers(elem)
+		if err != nil {
+			return fmt.Errorf("element %d is not an object", i)
+		}
+
+		for _, m := range members {
+			if _, ok := index[m.Key]; !ok {
+				index[m.Key] = len(columns)
+				columns = append(columns, m.Key)
+			}
+		}
+
+		rows[i] = members
+	}
+
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+
+	for _, members := range rows {
+		var (
+			record = make([]string, len(columns))
+			seen   = make(map[string]bool, len(members))
+		)
+
+		for _, m := range members {
+			// A repeated key keeps its first value, as Shape counts it
+			if seen[m.Key] {
+				continue
+			}
+			seen[m.Key] = true
+			record[index[m.Key]] = csvCell(m.Value)
+		}
+
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// Writes one value as the text of a cell
+func csvCell(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	typ, _ := JsonTypeOf(raw)
+
+	switch typ {
+	case String:
+		return unquote(raw)
+	case Null:
+		return ""
+	case Object, Array:
+		var out bytes.Buffer
+		if err := json.Compact(&out, raw); err == nil {
+			return out.String()
+		}
+	}
+
+	return string(raw)
+}
+
+// Reads CSV headed by a row of column names into an array of objects, one per row, with members in column order
+//
+// Cells are strings unless WithTypeInference is given, in which case a column whose filled cells are all JSON numbers holds numbers, one whose cells are all true or false holds booleans, and one whose cells are all JSON objects or arrays, as ToCSV writes nested values, holds them; empty cells are then null, as are all cells of a column with none filled. Numbers with leading zeros, such as postal codes, stay strings. Every row must have as many cells as the header. Honors WithDelimiter, WithTypeInference, and WithDecompression.
+func ReadCSV(r io.Reader, opts ...Option) (*RawArray, error) {
+	var (
+		cfg = newConfig(opts)
+		arr = make(RawArray, 0)
+	)
+
+	r, err := cfg.decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	in := csv.NewReader(r)
+
+	if cfg.delimiter != 0 {
+		in.Comma = cfg.delimiter
+	}
+
+	records, err := in.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv has no header")
+	}
+
+	header, rows := records[0], records[1:]
+	seen := make(map[string]bool, len(header))
+
+	for _, name := range header {
+		if seen[name] {
+			return nil, fmt.Errorf("csv column %q is repeated", name)
+		}
+		seen[name] = true
+	}
+
+	kinds := make([]JsonType, len(header))
+	for col := range header {
+		kinds[col] = String
+		if cfg.typeInference {
+			kinds[col] = csvColumnType(rows, col)
+		}
+	}
+
+	for _, row := range rows {
+		members := make([]member, len(header))
+
+		for col, name := range header {
+			members[col] = member{Key: name, Value: csvValue(row[col], kinds[col])}
+		}
+
+		arr = append(arr, marshalMembers(members))
+	}
+
+	return &arr, nil
+}
+
+// Infers the type a column's filled cells share: Number, True for true and false alike, Object for nested JSON, Null when none are filled, or else String
+func csvColumnType(rows [][]string, col int) JsonType {
+	kind := Undefined
+
+	for _, row := range rows {
+		cell := row[col]
+		if cell == "" {
+			continue
+		}
+
+		var found JsonType
+		switch typ, err := JsonTypeOf([]byte(cell)); {
+		case err != nil:
+			return String
+		case typ == True || typ == False:
+			found = True
+		case typ == Array:
+			found = Object
+		default:
+			found = typ
+		}
+
+		if found != Number && found != True && found != Object || kind != Undefined && kind != found {
+			return String
+		}
+		kind = found
+	}
+
+	if kind == Undefined {
+		return Null
+	}
+
+	return kind
+}
+
+// Encodes a cell as a value of the column's type
+func csvValue(cell string, kind JsonType) json.RawMessage {
+	if kind == String {
+		str, _ := json.Marshal(cell)
+		return str
+	}
+
+	if cell == "" {
+		return json.RawMessage("null")
+	}
+
+	if kind == Object {
+		var out bytes.Buffer
+		json.Compact(&out, []byte(cell))
+		return out.Bytes()
+	}
+
+	return json.RawMessage(cell)
+}
@@ -0,0 +1,94 @@
+package jsondescriber
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A number literal taken apart without rounding: its value is ±digits × 10^exp, with digits free of leading and trailing zeros, and empty for zero
+type decimal struct {
+	neg    bool
+	digits string
+	exp    int64
+}
+
+// Takes apart a valid number literal; false if its exponent does not fit an int64
+func parseDecimal(lit string) (decimal, bool) {
+	var d decimal
+
+	if strings.HasPrefix(lit, "-") {
+		d.neg, lit = true, lit[1:]
+	}
+
+	if i := strings.IndexAny(lit, "eE"); i >= 0 {
+		exp, err := strconv.ParseInt(lit[i+1:], 10, 64)
+		if err != nil {
+			return d, false
+		}
+		d.exp, lit = exp, lit[:i]
+	}
+
+	whole, frac, _ := strings.Cut(lit, ".")
+	digits := strings.TrimLeft(whole+frac, "0")
+	d.exp -= int64(len(frac))
+
+	trimmed := strings.TrimRight(digits, "0")
+	d.exp += int64(len(digits) - len(trimmed))
+	d.digits = trimmed
+
+	if d.digits == "" {
+		// -0 equals 0
+		return decimal{}, true
+	}
+
+	return d, true
+}
+
+// Writes the value in one form, as its digits and exponent, so that 150, 150.0, and 1.5e2 all read 15e1
+func (d decimal) String() string {
+	if d.digits == "" {
+		return "0"
+	}
+
+	sign := ""
+	if d.neg {
+		sign = "-"
+	}
+
+	return sign + d.digits + "e" + strconv.FormatInt(d.exp, 10)
+}
+
+// Orders two values exactly: -1 if d is smaller, 1 if larger, 0 if equal
+func (d decimal) cmp(o decimal) int {
+	switch {
+	case d.neg != o.neg:
+		if d.neg {
+			return -1
+		}
+		return 1
+	case d.neg:
+		return o.cmpMagnitude(d)
+	default:
+		return d.cmpMagnitude(o)
+	}
+}
+
+// Orders two values by absolute value
+func (d decimal) cmpMagnitude(o decimal) int {
+	if d.digits == "" || o.digits == "" {
+		// Zero has no digits, so it compares below any other
+		return strings.Compare(d.digits, o.digits)
+	}
+
+	// Compare where the leading digits fall, then the digits themselves, which without trailing zeros compare as text
+	dl, ol := d.exp+int64(len(d.digits)), o.exp+int64(len(o.digits))
+
+	switch {
+	case dl < ol:
+		return -1
+	case dl > ol:
+		return 1
+	}
+
+	return strings.Compare(d.digits, o.digits)
+}
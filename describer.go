@@ -0,0 +1,494 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Describes one document after another, reusing its working memory between calls
+//
+// A document may be given whole to Describe, or written in chunks as it arrives and then described by Finalize. A Describer is not safe for concurrent use; give each goroutine its own.
+type Describer struct {
+	cfg     *config
+	scan    scanner
+	counts  [len(jsonTypeNames)]uint
+	formats [len(stringFormatNames)]uint
+	// How many members each detector given to WithDetectors recognized, after a slot for none
+	detected []uint
+	keys     memberKeys
+	descr    JsonDescription
+	sample   sampler
+	// Describes member containers under WithNesting, one level shallower
+	child *Describer
+	// The options child's were derived from, so they can be derived again once cfg is replaced
+	childOf *config
+	// How many containers of each type have been folded into descr.Nested
+	combined [len(jsonTypeNames)]uint
+	// The bytes given to Write, awaiting Finalize
+	chunks chunks
+}
+
+// What is counted for one member: its type, for strings under WithStringFormats its format, and under WithDetectors which detector recognized it, counting from 1
+type memberKind struct {
+	typ      JsonType
+	format   StringFormat
+	detector int
+	// The member itself, kept only for containers under WithNesting
+	raw []byte
+}
+
+// Chooses which elements of a top-level array are counted under WithSampleSize
+type sampler struct {
+	seen      uint
+	reservoir []memberKind
+	rng       *rand.Rand
+	sample    Sample
+}
+
+// The keys of a top-level object in document order, kept as slices of the input so that finding repeats allocates nothing
+type memberKeys []memberKey
+
+type memberKey struct {
+	key  []byte
+	kind memberKind
+}
+
+func (k memberKeys) Len() int           { return len(k) }
+func (k memberKeys) Less(i, j int) bool { return bytes.Compare(k[i].key, k[j].key) < 0 }
+func (k memberKeys) Swap(i, j int)      { k[i], k[j] = k[j], k[i] }
+
+// Describers released by Describe, so that one-off calls also reuse memory
+var describerPool = sync.Pool{
+	New: func() interface{} {
+		return NewDescriber()
+	},
+}
+
+// Above this many remembered keys, Reset lets the key buffer go rather than keep a large allocation alive
+const maxRetainedKeys = 1 << 16
+
+// Constructor for Describer; the options apply to every document it describes
+//
+// Honors WithMaxDepth, WithMaxBytes, WithMaxMembers, WithParallelism, WithSampleSize, WithSeed, WithNumericStats, WithExactNumbers, WithStringFormats, WithNesting, WithKeyNames, WithLenient, WithHeuristics, WithDetectors, and WithPathStyle.
+func NewDescriber(opts ...Option) *Describer {
+	return &Describer{
+		cfg: newConfig(opts),
+		descr: JsonDescription{
+			Element: "undefined",
+			Members: make(map[string]uint),
+		},
+	}
+}
+
+// Describes data as the package-level Describe does
+//
+// The result belongs to the Describer and is overwritten by the next call or by Reset; copy it to keep it.
+func (d *Describer) Describe(data []byte) (*JsonDescription, error) {
+	return d.describe(context.Background(), data)
+}
+
+// Describes data, giving up with ctx.Err() once ctx is done
+func (d *Describer) describe(ctx context.Context, data []byte) (*JsonDescription, error) {
+	d.Reset()
+
+	if d.cfg.seeded {
+		seed := d.cfg.seed
+		d.descr.Seed = &seed
+	}
+
+	if d.cfg.heuristics != nil && len(d.cfg.heuristics.rules) > 0 {
+		typ, inner, err := d.cfg.heuristics.recognize(bytes.TrimSpace(data))
+
+		switch {
+		case err != nil:
+			return &d.descr, err
+		case inner == nil:
+			d.descr.Element = typ.String()
+			return &d.descr, nil
+		}
+
+		data = inner
+	}
+
+	// The parallel scan does not check for cancellation
+	if d.cfg.parallel && ctx.Done() == nil && d.cfg.sampleSize <= 0 && !d.cfg.numericStats && !d.cfg.stringFormats && !d.cfg.lenient && len(d.cfg.detectors) == 0 && d.cfg.nesting <= 0 && d.cfg.workers() > 1 && len(data) >= parallelMinBytes && (d.cfg.maxBytes == 0 || len(data) <= d.cfg.maxBytes) {
+		if d.describeArrayParallel(data) {
+			d.descr.Element = Array.String()
+			d.fillMembers()
+			return &d.descr, nil
+		}
+	}
+
+	d.scan = scanner{data: data, cfg: d.cfg, ctx: ctx}
+
+	if d.cfg.numericStats {
+		d.descr.Numbers = make(map[string]*NumberStats)
+		d.scan.onNumber = d.countNumber
+	}
+
+	if d.cfg.lenient {
+		d.scan.onSkip = d.skip
+	}
+
+	typ, err := d.scan.document(d.visit)
+	d.scan.data = nil
+
+	// Bail if this isn't even JSON
+	if err != nil {
+		d.Reset()
+		return &d.descr, err
+	}
+
+	if d.cfg.keyNames && typ == Object {
+		d.recordKeys()
+	}
+
+	d.forgetRepeats()
+	d.nestMembers()
+
+	// Nested members are described after the scan, and may have been cut short
+	if err := ctx.Err(); err != nil {
+		d.Reset()
+		return &d.descr, err
+	}
+	d.descr.Element = typ.String()
+
+	if typ == Array && d.cfg.sampleSize > 0 {
+		d.takeSample()
+	}
+
+	d.fillMembers()
+
+	return &d.descr, nil
+}
+
+// Copies the nonzero counts into the description
+func (d *Describer) fillMembers() {
+	for t, n := range d.counts {
+		if n > 0 {
+			d.descr.Members[JsonType(t).String()] = n
+		}
+	}
+
+	for f, n := range d.formats {
+		if f != int(NoFormat) && n > 0 {
+			if d.descr.Formats == nil {
+				d.descr.Formats = make(map[string]uint)
+			}
+			d.descr.Formats[StringFormat(f).String()] = n
+		}
+	}
+
+	for i, n := range d.detected {
+		if i > 0 && n > 0 {
+			if d.descr.Detected == nil {
+				d.descr.Detected = make(map[string]uint)
+			}
+			d.descr.Detected[d.cfg.detectors[i-1].Name()] += n
+		}
+	}
+}
+
+// Adds delta to the counts for one member
+func (d *Describer) tally(kind memberKind, delta int) {
+	d.counts[kind.typ] += uint(delta)
+	d.formats[kind.format] += uint(delta)
+
+	if kind.detector > 0 {
+		if d.detected == nil {
+			d.detected = make([]uint, len(d.cfg.detectors)+1)
+		}
+		d.detected[kind.detector] += uint(delta)
+	}
+}
+
+// Adds a number to the statistics for its path
+func (d *Describer) countNumber(path string, literal []byte) {
+	stats, ok := d.descr.Numbers[path]
+
+	if !ok {
+		stats = new(NumberStats)
+		d.descr.Numbers[path] = stats
+	}
+
+	stats.add(literal, d.cfg.exactNumbers)
+}
+
+// Records a malformed top-level member passed over under WithLenient
+func (d *Describer) skip(index int, key []byte, offset int, err error) {
+	skipped := &MemberError{Index: index, Offset: int64(offset), Err: err}
+
+	if key != nil {
+		skipped.Key = unquote(key)
+	}
+
+	d.descr.Skipped = append(d.descr.Skipped, skipped)
+}
+
+// Counts one top-level member, remembering object keys so repeats can be found afterwards
+func (d *Describer) visit(key []byte, typ JsonType, raw []byte) bool {
+	kind := memberKind{typ: typ}
+
+	if typ == String && d.cfg.stringFormats {
+		kind.format = DetectFormat(unquote(raw))
+	}
+
+	if len(d.cfg.detectors) > 0 {
+		kind.detector = d.cfg.detect(raw)
+	}
+
+	if typ.IsContainer() && d.cfg.nesting > 0 {
+		kind.raw = raw
+	}
+
+	if key != nil {
+		// Escapes are rare, so only those keys are decoded to compare by value; the rest are sliced out of their quotes
+		if bytes.IndexByte(key, '\\') >= 0 {
+			key = []byte(unquote(key))
+		} else {
+			key = key[1 : len(key)-1]
+		}
+		d.keys = append(d.keys, memberKey{key: key, kind: kind})
+	} else if d.cfg.sampleSize > 0 {
+		return d.offerSample(kind)
+	} else {
+		d.nest(kind)
+	}
+
+	d.tally(kind, 1)
+	return true
+}
+
+// Counts an array element if the sample has room for it, reporting whether the scan should go on
+func (d *Describer) offerSample(kind memberKind) bool {
+	var (
+		s = &d.sample
+		n = uint(d.cfg.sampleSize)
+	)
+
+	s.seen++
+
+	if !d.cfg.seeded {
+		if s.seen > n {
+			return false
+		}
+		d.tally(kind, 1)
+		d.nest(kind)
+		return true
+	}
+
+	// Reservoir sampling: every element ends up in the sample with equal probability
+	if s.seen <= n {
+		s.reservoir = append(s.reservoir, kind)
+		return true
+	}
+
+	if s.rng == nil {
+		s.rng = d.cfg.rand()
+	}
+
+	if j := uint(s.rng.Int63n(int64(s.seen))); j < n {
+		s.reservoir[j] = kind
+	}
+
+	return true
+}
+
+// Counts the sampled elements, marking the description as sampled if the array was longer than the sample
+func (d *Describer) takeSample() {
+	s := &d.sample
+
+	if d.cfg.seeded {
+		for _, kind := range s.reservoir {
+			d.tally(kind, 1)
+			d.nest(kind)
+		}
+	}
+
+	if s.seen <= uint(d.cfg.sampleSize) {
+		return
+	}
+
+	s.sample = Sample{Size: uint(d.cfg.sampleSize), Random: d.cfg.seeded}
+
+	if s.sample.Random {
+		s.sample.Seed = d.cfg.seed
+	}
+
+	d.descr.Sample = &s.sample
+}
+
+// Copies the distinct top-level keys into the description in document order, before forgetRepeats sorts them
+func (d *Describer) recordKeys() {
+	var (
+		keys = make([]string, 0, len(d.keys))
+		seen = make(map[string]bool, len(d.keys))
+	)
+
+	for _, k := range d.keys {
+		if !seen[string(k.key)] {
+			seen[string(k.key)] = true
+			keys = append(keys, string(k.key))
+		}
+	}
+
+	d.descr.Keys = keys
+}
+
+// Uncounts every value of a repeated key but the last, as json.Unmarshal would keep
+func (d *Describer) forgetRepeats() {
+	if len(d.keys) < 2 {
+		return
+	}
+
+	sort.Stable(d.keys)
+
+	for i := 1; i < len(d.keys); i++ {
+		if bytes.Equal(d.keys[i-1].key, d.keys[i].key) {
+			d.tally(d.keys[i-1].kind, -1)
+		}
+	}
+}
+
+// Folds the surviving value of each top-level key into descr.Nested; forgetRepeats has left repeats sorted together, last value last
+func (d *Describer) nestMembers() {
+	for i := range d.keys {
+		if i+1 < len(d.keys) && bytes.Equal(d.keys[i].key, d.keys[i+1].key) {
+			continue
+		}
+		d.nest(d.keys[i].kind)
+	}
+}
+
+// Describes a member container one level shallower and folds the result into descr.Nested
+func (d *Describer) nest(kind memberKind) {
+	if kind.raw == nil {
+		return
+	}
+
+	// The package-level Describe gives a pooled Describer new options, which the child must follow
+	if d.child == nil || d.childOf != d.cfg {
+		cfg := *d.cfg
+		cfg.nesting--
+		// Numbers are reported by path from the top, a sample only applies to the top-level array, and only top-level keys are named
+		cfg.numericStats = false
+		cfg.sampleSize = 0
+		cfg.parallel = false
+		cfg.keyNames = false
+		cfg.detectors = nil
+
+		if d.child == nil {
+			d.child = &Describer{descr: JsonDescription{Element: "undefined", Members: make(map[string]uint)}}
+		}
+		d.child.cfg = &cfg
+		d.childOf = d.cfg
+	}
+
+	// The parent scan has validated the member, so describing it again can only fail by cancellation, which the parent checks for
+	inner, _ := d.child.describe(d.scan.ctx, kind.raw)
+	name := kind.typ.String()
+
+	if d.descr.Nested == nil {
+		d.descr.Nested = make(map[string]*JsonDescription)
+	}
+
+	if prev, ok := d.descr.Nested[name]; ok {
+		prev.combine(inner, d.combined[kind.typ], 1)
+	} else {
+		first := &JsonDescription{Element: name, Members: make(map[string]uint), Uniform: true}
+		first.combine(inner, 0, 1)
+		d.descr.Nested[name] = first
+	}
+
+	d.combined[kind.typ]++
+}
+
+// Folds src, which combines m containers, into jd, which combines n containers of the same type
+//
+// Members and Formats are summed. jd stays Uniform only while both sides are and their containers hold the same counts each.
+func (jd *JsonDescription) combine(src *JsonDescription, n, m uint) {
+	if n > 0 && jd.Uniform {
+		for t, c := range src.Members {
+			if jd.Members[t]*m != c*n {
+				jd.Uniform = false
+			}
+		}
+		for t, c := range jd.Members {
+			if src.Members[t]*n != c*m {
+				jd.Uniform = false
+			}
+		}
+	}
+	jd.Uniform = jd.Uniform && (src.Uniform || m == 1)
+
+	// Nested descriptions combine as many containers as their parents count of that type
+	for t, inner := range src.Nested {
+		if jd.Nested == nil {
+			jd.Nested = make(map[string]*JsonDescription)
+		}
+		if prev, ok := jd.Nested[t]; ok {
+			prev.combine(inner, jd.Members[t], src.Members[t])
+			continue
+		}
+		first := &JsonDescription{Element: t, Members: make(map[string]uint), Uniform: true}
+		first.combine(inner, 0, src.Members[t])
+		jd.Nested[t] = first
+	}
+
+	for t, c := range src.Members {
+		jd.Members[t] += c
+	}
+
+	for f, c := range src.Formats {
+		if jd.Formats == nil {
+			jd.Formats = make(map[string]uint)
+		}
+		jd.Formats[f] += c
+	}
+}
+
+// Clears the last description, any remembered keys, and any bytes written but not yet finalized, keeping the options and allocated memory for reuse
+func (d *Describer) Reset() {
+	d.chunks = chunks{data: d.chunks.data[:0]}
+	d.scan.ctx = nil
+
+	if cap(d.keys) > maxRetainedKeys {
+		d.keys = nil
+	} else {
+		// Drop references into the last input so it can be collected
+		for i := range d.keys {
+			d.keys[i] = memberKey{}
+		}
+		d.keys = d.keys[:0]
+	}
+
+	for i := range d.sample.reservoir {
+		d.sample.reservoir[i].raw = nil
+	}
+
+	if d.child != nil {
+		d.child.Reset()
+	}
+
+	for k := range d.descr.Members {
+		delete(d.descr.Members, k)
+	}
+
+	d.counts = [len(jsonTypeNames)]uint{}
+	d.formats = [len(stringFormatNames)]uint{}
+	d.detected = nil
+	d.descr.Element = "undefined"
+	d.descr.Formats = nil
+	d.descr.Detected = nil
+	d.descr.Sample = nil
+	d.descr.Seed = nil
+	d.descr.Numbers = nil
+	d.descr.Nested = nil
+	d.descr.Keys = nil
+	d.descr.Skipped = nil
+	d.combined = [len(jsonTypeNames)]uint{}
+	d.sample = sampler{reservoir: d.sample.reservoir[:0]}
+}
@@ -0,0 +1,43 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+)
+
+// Recognizes members of a domain type, such as "ISO country code" or "money amount", for descriptions to report beside their JSON types
+//
+// Name is reported in JsonDescription.Detected and by Friendly, which pluralizes it as it does string formats. Match is given each top-level member as written, whitespace trimmed, and must not keep it or change it.
+type Detector interface {
+	Name() string
+	Match(raw json.RawMessage) bool
+}
+
+// A Detector built from a name and a function
+type funcDetector struct {
+	name  string
+	match func(json.RawMessage) bool
+}
+
+func (d *funcDetector) Name() string {
+	return d.name
+}
+
+func (d *funcDetector) Match(raw json.RawMessage) bool {
+	return d.match(raw)
+}
+
+// Constructor for a Detector reporting members for which match returns true under name
+func NewDetector(name string, match func(raw json.RawMessage) bool) Detector {
+	return &funcDetector{name: name, match: match}
+}
+
+// Finds the first detector that recognizes raw, counting from 1, or 0 if none does
+func (c *config) detect(raw []byte) int {
+	for i, d := range c.detectors {
+		if d.Match(raw) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,490 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The keys changed from one object to another, by category, as found by Compare
+type DiffResult struct {
+	// Keys only in the new object
+	Added []string
+	// Keys only in the old object
+	Deleted []string
+	// Keys whose values differ but keep their type
+	Modified []string
+	// Keys whose values keep their type but are less alike than WithReplaceThreshold, set apart from Modified
+	Replaced []string
+	// Keys whose values changed type
+	TypeChanged []string
+	// Keys spelled differently on each side but matched under WithCaseInsensitiveKeys or WithKeyNormalizer; they are listed in the other categories by their new spelling
+	Respelled []KeyPair
+	// Keys deleted and added with equal values, paired under WithRenameDetection instead of being listed in Deleted and Added
+	Renamed []KeyPair
+	// Whether each changed key above was missing, null, or set to a value on either side
+	Presence map[string]KeyPresence
+}
+
+// A key as spelled in the old object and in the new
+type KeyPair struct {
+	Old string
+	New string
+}
+
+// Reports whether key matching is loosened by WithCaseInsensitiveKeys or WithKeyNormalizer
+func (c *config) matchesKeys() bool {
+	return c.foldKeys || c.keyNormalizer != nil
+}
+
+// Maps a key to the form compared under WithKeyNormalizer and WithCaseInsensitiveKeys, normalizing before folding case
+func (c *config) matchKey(k string) string {
+	if c.keyNormalizer != nil {
+		k = c.keyNormalizer(k)
+	}
+
+	if c.foldKeys {
+		k = foldCase(k)
+	}
+
+	return k
+}
+
+// Maps each letter to the smallest in its Unicode case-folding orbit, so two strings fold to the same one exactly when strings.EqualFold holds
+func foldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		least := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < least {
+				least = f
+			}
+		}
+		return least
+	}, s)
+}
+
+// Pairs keys only in the old object with keys only in the new one that match once loosened, taking the keys of each in sorted order when several match alike
+func (c *config) respell(old, new RawObject) []KeyPair {
+	var (
+		pairs    = make([]KeyPair, 0)
+		unpaired = make(map[string][]string)
+	)
+
+	for _, k := range sortedKeys(old) {
+		if _, ok := new[k]; !ok {
+			m := c.matchKey(k)
+			unpaired[m] = append(unpaired[m], k)
+		}
+	}
+
+	for _, k := range sortedKeys(new) {
+		if _, ok := old[k]; ok {
+			continue
+		}
+
+		m := c.matchKey(k)
+		if olds := unpaired[m]; len(olds) > 0 {
+			pairs = append(pairs, KeyPair{Old: olds[0], New: k})
+			unpaired[m] = olds[1:]
+		}
+	}
+
+	return pairs
+}
+
+// Pairs deleted keys with added keys whose values are equal, as jsonEqual compares them, by a digest of each canonical value; when several values are equal, keys pair up in sorted order
+//
+// Returns the pairs and the keys of del and add left unpaired, all still sorted.
+func (c *config) renames(old, new RawObject, del, add []string) ([]KeyPair, []string, []string) {
+	var (
+		pairs   = make([]KeyPair, 0)
+		byValue = make(map[[sha256.Size]byte][]string)
+		paired  = make(map[string]bool)
+		digest  = func(k string, obj RawObject) [sha256.Size]byte {
+			raw, _ := obj.presence(k)
+			return sha256.Sum256([]byte(c.canonical(c.prune(raw, joinKey(PointerPath, "", k)))))
+		}
+	)
+
+	for _, k := range del {
+		sum := digest(k, old)
+		byValue[sum] = append(byValue[sum], k)
+	}
+
+	for _, k := range add {
+		sum := digest(k, new)
+		if olds := byValue[sum]; len(olds) > 0 {
+			pairs = append(pairs, KeyPair{Old: olds[0], New: k})
+			paired[olds[0]], paired[k] = true, true
+			byValue[sum] = olds[1:]
+		}
+	}
+
+	unpaired := func(keys []string) []string {
+		out := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if !paired[k] {
+				out = append(out, k)
+			}
+		}
+		return out
+	}
+
+	return pairs, unpaired(del), unpaired(add)
+}
+
+// Returns a copy of o with each respelled key moved to its new spelling, or o itself when there are none
+func (o RawObject) respelled(pairs []KeyPair) RawObject {
+	if len(pairs) == 0 {
+		return o
+	}
+
+	out := make(RawObject, len(o))
+	for k, v := range o {
+		out[k] = v
+	}
+
+	for _, p := range pairs {
+		out[p.New] = out[p.Old]
+		delete(out, p.Old)
+	}
+
+	return out
+}
+
+// Whether an object has a key, and whether its value is null
+//
+// A key set to JSON null is present, unlike a missing key: a key going from a value to null is typechanged, not deleted. A nil json.RawMessage in a RawObject counts as null.
+type Presence int
+
+const (
+	PresenceMissing Presence = iota
+	PresenceNull
+	PresenceValue
+)
+
+var presenceNames = [...]string{"missing", "null", "value"}
+
+// Returns "missing", "null", or "value"
+func (p Presence) String() string {
+	if p < 0 || int(p) >= len(presenceNames) {
+		return "invalid"
+	}
+
+	return presenceNames[p]
+}
+
+// Whether a changed key was present in the old and new objects
+type KeyPresence struct {
+	Old Presence
+	New Presence
+}
+
+// Looks up a key, telling a missing key from one set to null, and reading a nil value as null
+func (o RawObject) presence(k string) (json.RawMessage, Presence) {
+	raw, ok := o[k]
+
+	switch {
+	case !ok:
+		return nil, PresenceMissing
+	case raw == nil || string(bytes.TrimSpace(raw)) == "null":
+		return json.RawMessage("null"), PresenceNull
+	}
+
+	return raw, PresenceValue
+}
+
+// Reports whether nothing changed
+func (r *DiffResult) IsEmpty() bool {
+	return r.Total() == 0
+}
+
+// Counts the changed keys across every category
+func (r *DiffResult) Total() int {
+	return len(r.Added) + len(r.Deleted) + len(r.Modified) + len(r.Replaced) + len(r.TypeChanged) + len(r.Respelled) + len(r.Renamed)
+}
+
+// Converts the result to the map returned by Diff, keyed by "added", "deleted", "modified", and "typechanged"; Replaced keys are listed as modified, and Respelled and Renamed keys are left out
+func (r *DiffResult) Map() map[string][]string {
+	mod := r.Modified
+
+	if len(r.Replaced) > 0 {
+		mod = append(append(make([]string, 0, len(r.Modified)+len(r.Replaced)), r.Modified...), r.Replaced...)
+		sort.Strings(mod)
+	}
+
+	return map[string][]string{
+		"added":       r.Added,
+		"deleted":     r.Deleted,
+		"modified":    mod,
+		"typechanged": r.TypeChanged,
+	}
+}
+
+// One changed key as recorded by DiffDetailed, with its values compacted to text
+type ValueChange struct {
+	Key string
+	// The key in the old object, when WithCaseInsensitiveKeys or WithKeyNormalizer matched it to Key or WithRenameDetection paired it with Key; empty otherwise
+	OldKey string
+	// The values before and after, empty when the key was added or deleted
+	Old string
+	New string
+	// The types before and after, Undefined when the key was added or deleted
+	OldType JsonType
+	NewType JsonType
+	// Whether WithTruncate cut Old or New short
+	Truncated bool
+}
+
+// The keys changed from one object to another, by category, with the values on each side, as found by DiffDetailed
+type DetailedDiff struct {
+	Added       []ValueChange
+	Deleted     []ValueChange
+	Modified    []ValueChange
+	Replaced    []ValueChange
+	TypeChanged []ValueChange
+	// Keys spelled differently on each side, as in DiffResult.Respelled, by their new spelling with OldKey set
+	Respelled []ValueChange
+	// Keys paired under WithRenameDetection, as in DiffResult.Renamed, by their new name with OldKey set
+	Renamed []ValueChange
+}
+
+// Reports whether nothing changed
+func (d *DetailedDiff) IsEmpty() bool {
+	return d.Total() == 0
+}
+
+// Counts the changed keys across every category
+func (d *DetailedDiff) Total() int {
+	return len(d.Added) + len(d.Deleted) + len(d.Modified) + len(d.Replaced) + len(d.TypeChanged) + len(d.Respelled) + len(d.Renamed)
+}
+
+// this.DiffDetailed(that) is Compare with the old and new value and type of each changed key
+//
+// Honors the same options as Compare, and WithTruncate.
+func (o *RawObject) DiffDetailed(n *RawObject, opts ...Option) *DetailedDiff {
+	var (
+		cfg  = newConfig(opts)
+		diff = o.Compare(n, opts...)
+		this = (*o).respelled(diff.Respelled)
+		that = *n
+		was  = make(map[string]string, len(diff.Respelled))
+		resp = make([]string, 0, len(diff.Respelled))
+	)
+
+	for _, p := range diff.Respelled {
+		was[p.New] = p.Old
+		resp = append(resp, p.New)
+	}
+
+	renamed := make([]ValueChange, 0, len(diff.Renamed))
+
+	for _, p := range diff.Renamed {
+		c := ValueChange{Key: p.New, OldKey: p.Old}
+		old, new := this[p.Old], that[p.New]
+		var oldCut, newCut bool
+
+		c.OldType, _ = JsonTypeOf(old)
+		c.Old, oldCut = cfg.preview(old)
+		c.NewType, _ = JsonTypeOf(new)
+		c.New, newCut = cfg.preview(new)
+
+		c.Truncated = oldCut || newCut
+		renamed = append(renamed, c)
+	}
+
+	detail := func(keys []string) []ValueChange {
+		changes := make([]ValueChange, 0, len(keys))
+
+		for _, k := range keys {
+			c := ValueChange{Key: k, OldKey: was[k]}
+			var oldCut, newCut bool
+
+			if old, ok := this[k]; ok {
+				c.OldType, _ = JsonTypeOf(old)
+				c.Old, oldCut = cfg.preview(old)
+			}
+			if new, ok := that[k]; ok {
+				c.NewType, _ = JsonTypeOf(new)
+				c.New, newCut = cfg.preview(new)
+			}
+
+			c.Truncated = oldCut || newCut
+			changes = append(changes, c)
+		}
+
+		return changes
+	}
+
+	return &DetailedDiff{
+		Added:       detail(diff.Added),
+		Deleted:     detail(diff.Deleted),
+		Modified:    detail(diff.Modified),
+		Replaced:    detail(diff.Replaced),
+		TypeChanged: detail(diff.TypeChanged),
+		Respelled:   detail(resp),
+		Renamed:     renamed,
+	}
+}
+
+// Compacts a value to text, cutting it to WithTruncate bytes at a character boundary and marking the cut with an ellipsis
+func (c *config) preview(raw json.RawMessage) (string, bool) {
+	var out bytes.Buffer
+
+	if err := json.Compact(&out, raw); err != nil {
+		out.Reset()
+		out.Write(raw)
+	}
+
+	return c.cut(out.String())
+}
+
+// Cuts text to WithTruncate bytes at a character boundary, marking the cut with an ellipsis
+func (c *config) cut(text string) (string, bool) {
+	if c.truncate <= 0 || len(text) <= c.truncate {
+		return text, false
+	}
+
+	cut := c.truncate
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	return text[:cut] + "…", true
+}
+
+// A Comparator's ruling on a key present on both sides of a Compare
+type DiffVerdict int
+
+const (
+	// Defer to the next comparator, and finally to the built-in type and byte comparison
+	VerdictDefault DiffVerdict = iota
+	VerdictUnchanged
+	VerdictModified
+	VerdictTypeChanged
+)
+
+// Overrides how Compare compares the old and new values of a key present in both objects
+type Comparator func(key string, old, new json.RawMessage) DiffVerdict
+
+// Asks each comparator in turn for a verdict, stopping at the first that does not defer
+func (c *config) compare(key string, old, new json.RawMessage) DiffVerdict {
+	for _, cmp := range c.comparators {
+		if v := cmp(key, old, new); v != VerdictDefault {
+			return v
+		}
+	}
+
+	return VerdictDefault
+}
+
+// Reports whether a member with this key, located at ptr, is excluded from comparison
+func (c *config) ignores(key, ptr string) bool {
+	for _, glob := range c.ignoreKeys {
+		if ok, _ := path.Match(glob, key); ok {
+			return true
+		}
+	}
+
+	for _, glob := range c.ignorePaths {
+		if ok, _ := path.Match(glob, ptr); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns raw with every ignored member beneath ptr removed and, under WithUnorderedArrays, arrays and object keys sorted; returns raw itself when neither applies
+func (c *config) prune(raw json.RawMessage, ptr string) json.RawMessage {
+	if len(c.ignoreKeys) == 0 && len(c.ignorePaths) == 0 && !c.unorderedArrays {
+		return raw
+	}
+
+	out, err := c.normalize(raw, ptr)
+
+	if err != nil {
+		return raw
+	}
+
+	return out
+}
+
+func (c *config) normalize(raw json.RawMessage, ptr string) ([]byte, error) {
+	var out bytes.Buffer
+
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := make([]member, 0, len(members))
+		for _, m := range members {
+			child := joinKey(PointerPath, ptr, m.Key)
+			if c.ignores(m.Key, child) {
+				continue
+			}
+			if m.Value, err = c.normalize(m.Value, child); err != nil {
+				return nil, err
+			}
+			kept = append(kept, m)
+		}
+
+		if c.unorderedArrays {
+			sort.SliceStable(kept, func(i, j int) bool {
+				return kept[i].Key < kept[j].Key
+			})
+		}
+
+		out.WriteByte('{')
+		for i, m := range kept {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			k, _ := json.Marshal(m.Key)
+			out.Write(k)
+			out.WriteByte(':')
+			out.Write(m.Value)
+		}
+		out.WriteByte('}')
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return nil, err
+		}
+
+		elems := make([]string, len(arr))
+		for i := range arr {
+			elem, err := c.normalize(arr[i], joinIndex(PointerPath, ptr, i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = string(elem)
+		}
+
+		if c.unorderedArrays {
+			sort.Strings(elems)
+		}
+
+		out.WriteByte('[')
+		out.WriteString(strings.Join(elems, ","))
+		out.WriteByte(']')
+
+	default:
+		if err := json.Compact(&out, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
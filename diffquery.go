@@ -0,0 +1,104 @@
+package jsondescriber
+
+import "strings"
+
+// Keeps only the added keys
+func (r *DiffResult) OnlyAdded() *DiffResult {
+	return &DiffResult{Added: r.Added, Deleted: []string{}, Modified: []string{}, TypeChanged: []string{}}
+}
+
+// Keeps only the deleted keys
+func (r *DiffResult) OnlyDeleted() *DiffResult {
+	return &DiffResult{Added: []string{}, Deleted: r.Deleted, Modified: []string{}, TypeChanged: []string{}}
+}
+
+// Keeps only the modified keys
+func (r *DiffResult) OnlyModified() *DiffResult {
+	return &DiffResult{Added: []string{}, Deleted: []string{}, Modified: r.Modified, TypeChanged: []string{}}
+}
+
+// Keeps only the keys whose type changed
+func (r *DiffResult) OnlyTypeChanged() *DiffResult {
+	return &DiffResult{Added: []string{}, Deleted: []string{}, Modified: []string{}, TypeChanged: r.TypeChanged}
+}
+
+// Keeps only changes likely to break a consumer of the old object: deleted keys and changed types
+func (r *DiffResult) OnlyBreaking() *DiffResult {
+	return &DiffResult{Added: []string{}, Deleted: r.Deleted, Modified: []string{}, TypeChanged: r.TypeChanged}
+}
+
+// Keeps only keys at or beneath a JSON Pointer, e.g. "/spec" keeps "spec" but not "specs"; "" keeps everything
+//
+// A key enclosing the pointer is kept as well, since "/spec/replicas" may be what changed within "spec".
+func (r *DiffResult) Under(prefix string) *DiffResult {
+	var (
+		self = func(k string) string { return k }
+		ok   = func(k string) bool { return underPointer(k, prefix) }
+	)
+
+	return &DiffResult{
+		Added:       keepChanges(r.Added, self, ok),
+		Deleted:     keepChanges(r.Deleted, self, ok),
+		Modified:    keepChanges(r.Modified, self, ok),
+		TypeChanged: keepChanges(r.TypeChanged, self, ok),
+	}
+}
+
+// Keeps only the added keys
+func (d *DetailedDiff) OnlyAdded() *DetailedDiff {
+	return &DetailedDiff{Added: d.Added, Deleted: []ValueChange{}, Modified: []ValueChange{}, TypeChanged: []ValueChange{}}
+}
+
+// Keeps only the deleted keys
+func (d *DetailedDiff) OnlyDeleted() *DetailedDiff {
+	return &DetailedDiff{Added: []ValueChange{}, Deleted: d.Deleted, Modified: []ValueChange{}, TypeChanged: []ValueChange{}}
+}
+
+// Keeps only the modified keys
+func (d *DetailedDiff) OnlyModified() *DetailedDiff {
+	return &DetailedDiff{Added: []ValueChange{}, Deleted: []ValueChange{}, Modified: d.Modified, TypeChanged: []ValueChange{}}
+}
+
+// Keeps only the keys whose type changed
+func (d *DetailedDiff) OnlyTypeChanged() *DetailedDiff {
+	return &DetailedDiff{Added: []ValueChange{}, Deleted: []ValueChange{}, Modified: []ValueChange{}, TypeChanged: d.TypeChanged}
+}
+
+// Keeps only changes likely to break a consumer of the old object: deleted keys and changed types
+func (d *DetailedDiff) OnlyBreaking() *DetailedDiff {
+	return &DetailedDiff{Added: []ValueChange{}, Deleted: d.Deleted, Modified: []ValueChange{}, TypeChanged: d.TypeChanged}
+}
+
+// Keeps only keys at or beneath a JSON Pointer, as DiffResult.Under does
+func (d *DetailedDiff) Under(prefix string) *DetailedDiff {
+	var (
+		key = func(c ValueChange) string { return c.Key }
+		ok  = func(k string) bool { return underPointer(k, prefix) }
+	)
+
+	return &DetailedDiff{
+		Added:       keepChanges(d.Added, key, ok),
+		Deleted:     keepChanges(d.Deleted, key, ok),
+		Modified:    keepChanges(d.Modified, key, ok),
+		TypeChanged: keepChanges(d.TypeChanged, key, ok),
+	}
+}
+
+// Filters changes by their key, never returning nil
+func keepChanges[T any](changes []T, key func(T) string, ok func(string) bool) []T {
+	kept := make([]T, 0, len(changes))
+
+	for _, c := range changes {
+		if ok(key(c)) {
+			kept = append(kept, c)
+		}
+	}
+
+	return kept
+}
+
+// Reports whether a top-level key's JSON Pointer is prefix, lies beneath it, or encloses it
+func underPointer(key, prefix string) bool {
+	ptr := joinKey(PointerPath, "", key)
+	return prefix == "" || ptr == prefix || strings.HasPrefix(ptr, prefix+"/") || strings.HasPrefix(prefix, ptr+"/")
+}
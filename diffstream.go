@@ -0,0 +1,263 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// One change found by DiffStream
+type DiffEvent struct {
+	// JSON Pointer of the member that changed; empty for the whole document
+	Path string
+	// One of "added", "deleted", "modified", or "typechanged", as keyed in Diff's map
+	Op string
+	// The types before and after, Undefined when the member was added or deleted
+	OldType JsonType
+	NewType JsonType
+}
+
+// Compares two documents read from old and new token by token, handing each change to fn as it is found, for documents too large to hold as RawObjects
+//
+// Old is read first and kept only as a digest of each object member, so memory grows with the number of keys in old rather than the size of its values. Objects are descended into and their members compared one by one, so a change deep within reports the member's full path; arrays and scalars are compared whole, with a changed element reporting its array as modified. Values are compared as parsed, so key order, whitespace, and escapes do not matter, and numbers must be written alike. Added, modified, and type-changed members are reported in the order new holds them, then deleted members ordered by path once new has been read to the end. Returning an error from fn stops the comparison with that error, and ctx is checked as tokens are read. Honors WithIgnoreKeys, WithIgnorePaths, WithUnorderedArrays, WithMaxDepth, WithMaxBytes, WithMaxMembers, and WithDecompression; comparators given with WithComparator are not consulted, as values are never held whole.
+func DiffStream(ctx context.Context, old, new io.Reader, fn func(DiffEvent) error, opts ...Option) error {
+	d := &streamDiff{cfg: newConfig(opts), old: make(map[string]*streamEntry), fn: fn}
+
+	old, err := d.cfg.decompress(old)
+	if err != nil {
+		return err
+	}
+
+	w := newTokenWalker(ctx, old, d.cfg)
+
+	tok, err := w.token()
+	if err != nil {
+		return err
+	}
+
+	root, err := d.index(w, tok, "", true)
+	if err != nil {
+		return err
+	}
+
+	if err = w.finish(); err != nil {
+		return err
+	}
+
+	d.old[""] = root
+
+	if new, err = d.cfg.decompress(new); err != nil {
+		return err
+	}
+
+	w = newTokenWalker(ctx, new, d.cfg)
+
+	if tok, err = w.token(); err != nil {
+		return err
+	}
+
+	if err = d.compare(w, tok, "", root); err != nil {
+		return err
+	}
+
+	if err = w.finish(); err != nil {
+		return err
+	}
+
+	return d.deleted()
+}
+
+// A value of the old document, as DiffStream remembers it
+type streamEntry struct {
+	typ JsonType
+	sum [sha256.Size]byte
+	// Whether new had a member here, and whether both were objects and so compared member by member
+	seen      bool
+	descended bool
+}
+
+// The state of one DiffStream
+type streamDiff struct {
+	cfg *config
+	// Every object member of old outside arrays, by JSON Pointer
+	old map[string]*streamEntry
+	fn  func(DiffEvent) error
+}
+
+// Reads the rest of the value tok begins at ptr, returning its type and a digest equal for values equal as parsed; under record, members of objects not within arrays are remembered by pointer
+func (d *streamDiff) index(w *tokenWalker, tok json.Token, ptr string, record bool) (*streamEntry, error) {
+	var (
+		entry = &streamEntry{typ: tokenType(tok)}
+		h     = sha256.New()
+	)
+
+	h.Write([]byte{byte(entry.typ)})
+
+	switch entry.typ {
+	case Object:
+		sums := make([][]byte, 0)
+
+		for w.dec.More() {
+			key, err := w.token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := w.token()
+			if err != nil {
+				return nil, err
+			}
+
+			child := joinKey(PointerPath, ptr, key.(string))
+
+			if d.cfg.ignores(key.(string), child) {
+				if err = w.skip(val); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			member, err := d.index(w, val, child, record)
+			if err != nil {
+				return nil, err
+			}
+
+			if record {
+				d.old[child] = member
+			}
+
+			// Hash each member on its own so that key order cannot change the object's digest
+			mh := sha256.New()
+			mh.Write([]byte(key.(string)))
+			mh.Write(member.sum[:])
+			sums = append(sums, mh.Sum(nil))
+		}
+
+		sort.Slice(sums, func(i, j int) bool { return bytes.Compare(sums[i], sums[j]) < 0 })
+
+		for _, sum := range sums {
+			h.Write(sum)
+		}
+
+		if _, err := w.token(); err != nil {
+			return nil, err
+		}
+
+	case Array:
+		sums := make([][]byte, 0)
+
+		for i := 0; w.dec.More(); i++ {
+			val, err := w.token()
+			if err != nil {
+				return nil, err
+			}
+
+			elem, err := d.index(w, val, joinIndex(PointerPath, ptr, i), false)
+			if err != nil {
+				return nil, err
+			}
+
+			sums = append(sums, elem.sum[:])
+		}
+
+		if d.cfg.unorderedArrays {
+			sort.Slice(sums, func(i, j int) bool { return bytes.Compare(sums[i], sums[j]) < 0 })
+		}
+
+		for _, sum := range sums {
+			h.Write(sum)
+		}
+
+		if _, err := w.token(); err != nil {
+			return nil, err
+		}
+
+	case String:
+		h.Write([]byte(tok.(string)))
+
+	case Number:
+		h.Write([]byte(tok.(json.Number)))
+	}
+
+	h.Sum(entry.sum[:0])
+	return entry, nil
+}
+
+// Reads the rest of the value of new that tok begins at ptr, reporting how it differs from old's value there
+func (d *streamDiff) compare(w *tokenWalker, tok json.Token, ptr string, old *streamEntry) error {
+	if old.typ != Object || tok != json.Delim('{') {
+		cur, err := d.index(w, tok, ptr, false)
+
+		switch {
+		case err != nil:
+			return err
+		case cur.typ != old.typ:
+			return d.fn(DiffEvent{Path: ptr, Op: "typechanged", OldType: old.typ, NewType: cur.typ})
+		case cur.sum != old.sum:
+			return d.fn(DiffEvent{Path: ptr, Op: "modified", OldType: old.typ, NewType: cur.typ})
+		}
+
+		return nil
+	}
+
+	old.descended = true
+
+	for w.dec.More() {
+		key, err := w.token()
+		if err != nil {
+			return err
+		}
+
+		val, err := w.token()
+		if err != nil {
+			return err
+		}
+
+		child := joinKey(PointerPath, ptr, key.(string))
+		prev, ok := d.old[child]
+
+		switch {
+		case d.cfg.ignores(key.(string), child):
+			err = w.skip(val)
+		case !ok:
+			if err = d.fn(DiffEvent{Path: child, Op: "added", NewType: tokenType(val)}); err == nil {
+				err = w.skip(val)
+			}
+		default:
+			prev.seen = true
+			err = d.compare(w, val, child, prev)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.token()
+	return err
+}
+
+// Reports the members of old missing from objects that new also had, ordered by path
+func (d *streamDiff) deleted() error {
+	for _, ptr := range sortedKeys(d.old) {
+		entry := d.old[ptr]
+
+		if ptr == "" || entry.seen {
+			continue
+		}
+
+		if parent := d.old[ptr[:strings.LastIndexByte(ptr, '/')]]; !parent.descended {
+			continue
+		}
+
+		if err := d.fn(DiffEvent{Path: ptr, Op: "deleted", OldType: entry.typ}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
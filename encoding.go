@@ -0,0 +1,303 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The version written by MarshalJSON for JsonDescription and Shape; UnmarshalJSON rejects any other
+//
+// Version 1 looks like:
+//
+//	{"version":1,"element":"array","members":{"number":3},
+//	 "sample":{"size":3,"random":true,"seed":7},"seed":7,
+//	 "numbers":{"/*":{"count":3,"integers":3,"min":1,"max":3,"mean":2}},
+//	 "formats":{"UUID":1}}
+//
+// for a JsonDescription, where sample, seed, numbers, formats, nested, and keys are omitted when unset, and
+//
+//	{"version":1,"count":2,"types":{"object":2},"keys":["id"],
+//	 "fields":{"id":{"count":2,"types":{"number":2}}}}
+//
+// for a Shape, where empty parts are omitted. Nested descriptions and shapes carry no version. Fields added in a later version will not change the meaning of these.
+const EncodingVersion = 1
+
+type descriptionJson struct {
+	Version  int                         `json:"version,omitempty"`
+	Element  string                      `json:"element"`
+	Members  map[string]uint             `json:"members"`
+	Sample   *sampleJson                 `json:"sample,omitempty"`
+	Seed     *int64                      `json:"seed,omitempty"`
+	Numbers  map[string]*numbersJson     `json:"numbers,omitempty"`
+	Formats  map[string]uint             `json:"formats,omitempty"`
+	Detected map[string]uint             `json:"detected,omitempty"`
+	Nested   map[string]*descriptionJson `json:"nested,omitempty"`
+	Uniform  bool                        `json:"uniform,omitempty"`
+	Keys     []string                    `json:"keys,omitempty"`
+	Skipped  []*skippedJson              `json:"skipped,omitempty"`
+}
+
+type skippedJson struct {
+	Index  int    `json:"index"`
+	Key    string `json:"key,omitempty"`
+	Offset int64  `json:"offset"`
+	Error  string `json:"error"`
+}
+
+type sampleJson struct {
+	Size   uint  `json:"size"`
+	Random bool  `json:"random"`
+	Seed   int64 `json:"seed,omitempty"`
+}
+
+type numbersJson struct {
+	Count    uint    `json:"count"`
+	Integers uint    `json:"integers"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Mean     float64 `json:"mean"`
+	MinText  string  `json:"minText,omitempty"`
+	MaxText  string  `json:"maxText,omitempty"`
+}
+
+type shapeJson struct {
+	Version  int                   `json:"version,omitempty"`
+	Count    uint                  `json:"count"`
+	Types    map[string]uint       `json:"types,omitempty"`
+	Integers uint                  `json:"integers,omitempty"`
+	Example  json.RawMessage       `json:"example,omitempty"`
+	Formats  map[string]uint       `json:"formats,omitempty"`
+	Spaced   uint                  `json:"spaced,omitempty"`
+	Longest  uint                  `json:"longest,omitempty"`
+	Keys     []string              `json:"keys,omitempty"`
+	Fields   map[string]*shapeJson `json:"fields,omitempty"`
+	Items    *shapeJson            `json:"items,omitempty"`
+}
+
+// Implements json.Marshaler, writing the format described at EncodingVersion
+//
+// Numbers too large for float64 were recorded as ±Inf, which JSON cannot represent, so a description holding one fails to marshal.
+func (jd JsonDescription) MarshalJSON() ([]byte, error) {
+	out := jd.encode()
+	out.Version = EncodingVersion
+
+	return json.Marshal(out)
+}
+
+// Implements json.Unmarshaler, reading the format described at EncodingVersion
+func (jd *JsonDescription) UnmarshalJSON(data []byte) error {
+	var in descriptionJson
+
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	if in.Version != EncodingVersion {
+		return fmt.Errorf("unsupported json description version %d", in.Version)
+	}
+
+	decoded, err := in.decode()
+	if err != nil {
+		return err
+	}
+
+	*jd = *decoded
+	return nil
+}
+
+// Converts a JsonDescription and its nested descriptions to the wire form, without a version
+func (jd *JsonDescription) encode() *descriptionJson {
+	out := &descriptionJson{
+		Element:  jd.Element,
+		Members:  jd.Members,
+		Formats:  jd.Formats,
+		Detected: jd.Detected,
+		Seed:     jd.Seed,
+		Uniform:  jd.Uniform,
+		Keys:     jd.Keys,
+	}
+
+	if out.Members == nil {
+		out.Members = make(map[string]uint)
+	}
+
+	if jd.Sample != nil {
+		out.Sample = &sampleJson{Size: jd.Sample.Size, Random: jd.Sample.Random, Seed: jd.Sample.Seed}
+	}
+
+	if len(jd.Numbers) > 0 {
+		out.Numbers = make(map[string]*numbersJson, len(jd.Numbers))
+		for path, s := range jd.Numbers {
+			out.Numbers[path] = &numbersJson{Count: s.Count, Integers: s.Integers, Min: s.Min, Max: s.Max, Mean: s.Mean, MinText: s.MinText, MaxText: s.MaxText}
+		}
+	}
+
+	for _, e := range jd.Skipped {
+		out.Skipped = append(out.Skipped, &skippedJson{Index: e.Index, Key: e.Key, Offset: e.Offset, Error: e.Err.Error()})
+	}
+
+	if len(jd.Nested) > 0 {
+		out.Nested = make(map[string]*descriptionJson, len(jd.Nested))
+		for t, inner := range jd.Nested {
+			out.Nested[t] = inner.encode()
+		}
+	}
+
+	return out
+}
+
+// Rebuilds a JsonDescription, checking its element type and those of its nested descriptions
+func (in *descriptionJson) decode() (*JsonDescription, error) {
+	if ParseJsonType(in.Element) == Undefined && in.Element != Undefined.String() {
+		return nil, fmt.Errorf("json description element %q is not a json type", in.Element)
+	}
+
+	jd := &JsonDescription{
+		Element:  in.Element,
+		Members:  in.Members,
+		Formats:  in.Formats,
+		Detected: in.Detected,
+		Seed:     in.Seed,
+		Uniform:  in.Uniform,
+		Keys:     in.Keys,
+	}
+
+	if jd.Members == nil {
+		jd.Members = make(map[string]uint)
+	}
+
+	if in.Sample != nil {
+		jd.Sample = &Sample{Size: in.Sample.Size, Random: in.Sample.Random, Seed: in.Sample.Seed}
+	}
+
+	if in.Numbers != nil {
+		jd.Numbers = make(map[string]*NumberStats, len(in.Numbers))
+		for path, s := range in.Numbers {
+			if s == nil {
+				return nil, fmt.Errorf("json description numbers at %q: missing statistics", path)
+			}
+			jd.Numbers[path] = &NumberStats{Count: s.Count, Integers: s.Integers, Min: s.Min, Max: s.Max, Mean: s.Mean, MinText: s.MinText, MaxText: s.MaxText}
+		}
+	}
+
+	for i, e := range in.Skipped {
+		if e == nil {
+			return nil, fmt.Errorf("json description skipped member %d: missing error", i)
+		}
+		// Only the message is encoded, so the error is rebuilt around ErrInvalidJson, which every skipped member's matches
+		err := fmt.Errorf("%w%s", ErrInvalidJson, strings.TrimPrefix(e.Error, ErrInvalidJson.Error()))
+		jd.Skipped = append(jd.Skipped, &MemberError{Index: e.Index, Key: e.Key, Offset: e.Offset, Err: err})
+	}
+
+	if in.Nested != nil {
+		jd.Nested = make(map[string]*JsonDescription, len(in.Nested))
+		for t, nested := range in.Nested {
+			if nested == nil {
+				return nil, fmt.Errorf("json description nested %s: missing description", t)
+			}
+			inner, err := nested.decode()
+			if err != nil {
+				return nil, err
+			}
+			jd.Nested[t] = inner
+		}
+	}
+
+	return jd, nil
+}
+
+// Implements json.Marshaler, writing the format described at EncodingVersion
+func (s *Shape) MarshalJSON() ([]byte, error) {
+	out := s.encode()
+	out.Version = EncodingVersion
+
+	return json.Marshal(out)
+}
+
+// Implements json.Unmarshaler, reading the format described at EncodingVersion
+func (s *Shape) UnmarshalJSON(data []byte) error {
+	var in shapeJson
+
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	if in.Version != EncodingVersion {
+		return fmt.Errorf("unsupported shape version %d", in.Version)
+	}
+
+	decoded, err := in.decode("")
+	if err != nil {
+		return err
+	}
+
+	*s = *decoded
+	return nil
+}
+
+// Converts a Shape and everything nested in it to the wire form, without a version
+func (s *Shape) encode() *shapeJson {
+	out := &shapeJson{Count: s.Count, Integers: s.Integers, Example: s.Example, Formats: s.Formats, Spaced: s.Spaced, Longest: s.MaxLength, Keys: s.Keys}
+
+	if len(s.Types) > 0 {
+		out.Types = s.Types
+	}
+
+	if len(s.Fields) > 0 {
+		out.Fields = make(map[string]*shapeJson, len(s.Fields))
+		for k, f := range s.Fields {
+			out.Fields[k] = f.encode()
+		}
+	}
+
+	if s.Items != nil {
+		out.Items = s.Items.encode()
+	}
+
+	return out
+}
+
+// Rebuilds a Shape, checking that keys and fields agree; at is the pointer to this shape, for errors
+func (in *shapeJson) decode(at string) (*Shape, error) {
+	s := NewShape()
+	s.Count = in.Count
+	s.Integers = in.Integers
+	s.Example = in.Example
+	s.Formats = in.Formats
+	s.Spaced = in.Spaced
+	s.MaxLength = in.Longest
+
+	for t, n := range in.Types {
+		s.Types[t] = n
+	}
+
+	if len(in.Keys) != len(in.Fields) {
+		return nil, fmt.Errorf("shape at %q: %d keys but %d fields", at, len(in.Keys), len(in.Fields))
+	}
+
+	for _, k := range in.Keys {
+		f, ok := in.Fields[k]
+		if !ok || f == nil {
+			return nil, fmt.Errorf("shape at %q: no field for key %s", at, SafeKey(k))
+		}
+
+		child, err := f.decode(joinKey(PointerPath, at, k))
+		if err != nil {
+			return nil, err
+		}
+
+		s.Keys = append(s.Keys, k)
+		s.Fields[k] = child
+	}
+
+	if in.Items != nil {
+		items, err := in.Items.decode(joinWildcard(PointerPath, at))
+		if err != nil {
+			return nil, err
+		}
+		s.Items = items
+	}
+
+	return s, nil
+}
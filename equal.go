@@ -0,0 +1,178 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// this.Equal(that) reports whether two objects hold the same members with equal values, compared recursively as parsed JSON
+//
+// Key order and insignificant whitespace do not matter, escapes are decoded before strings are compared, and numbers are equal when they denote the same value, so 1, 1.0, and 1e0 match. Array elements must match in order unless WithUnorderedArrays is given. Honors WithUnorderedArrays and WithExactNumbers.
+func (o *RawObject) Equal(n *RawObject, opts ...Option) bool {
+	var (
+		cfg  = newConfig(opts)
+		this = *o
+		that = *n
+	)
+
+	if len(this) != len(that) {
+		return false
+	}
+
+	for k, v := range this {
+		w, ok := that[k]
+		if !ok || !cfg.jsonEqual(v, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// this.Equal(that) reports whether two arrays hold equal elements, compared as RawObject.Equal compares values
+//
+// Elements must match in order; under WithUnorderedArrays they must match as multisets, so [1,2,2] equals [2,1,2] but not [1,2]. Honors WithUnorderedArrays and WithExactNumbers.
+func (a *RawArray) Equal(n *RawArray, opts ...Option) bool {
+	var (
+		cfg  = newConfig(opts)
+		this = *a
+		that = *n
+	)
+
+	if len(this) != len(that) {
+		return false
+	}
+
+	if !cfg.unorderedArrays {
+		for i := range this {
+			if !cfg.jsonEqual(this[i], that[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	counts := make(map[string]int, len(this))
+
+	for i := range this {
+		counts[cfg.canonical(this[i])]++
+	}
+
+	for i := range that {
+		key := cfg.canonical(that[i])
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+
+	return true
+}
+
+// Compares two raw values as parsed JSON; invalid JSON is equal only to identical bytes
+func (c *config) jsonEqual(a, b json.RawMessage) bool {
+	a, b = bytes.TrimSpace(a), bytes.TrimSpace(b)
+
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	if _, err := JsonTypeOf(a); err != nil {
+		return false
+	}
+
+	if _, err := JsonTypeOf(b); err != nil {
+		return false
+	}
+
+	return c.canonical(a) == c.canonical(b)
+}
+
+// Encodes a valid value so that equal values encode identically: keys sorted, strings re-escaped, numbers in a single form, and under WithUnorderedArrays array elements sorted
+func (c *config) canonical(raw json.RawMessage) string {
+	var sb strings.Builder
+
+	c.writeCanonical(&sb, bytes.TrimSpace(raw))
+	return sb.String()
+}
+
+func (c *config) writeCanonical(sb *strings.Builder, raw json.RawMessage) {
+	typ, _ := JsonTypeOf(raw)
+
+	switch typ {
+	case Object:
+		obj := make(RawObject)
+		json.Unmarshal(raw, &obj)
+
+		sb.WriteByte('{')
+		for i, k := range sortedKeys(obj) {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(strconv.Quote(k))
+			sb.WriteByte(':')
+			c.writeCanonical(sb, obj[k])
+		}
+		sb.WriteByte('}')
+
+	case Array:
+		arr := make(RawArray, 0)
+		json.Unmarshal(raw, &arr)
+
+		elems := make([]string, len(arr))
+		for i := range arr {
+			elems[i] = c.canonical(arr[i])
+		}
+
+		if c.unorderedArrays {
+			sort.Strings(elems)
+		}
+
+		sb.WriteByte('[')
+		sb.WriteString(strings.Join(elems, ","))
+		sb.WriteByte(']')
+
+	case String:
+		sb.WriteString(strconv.Quote(unquote(raw)))
+
+	case Number:
+		if c.exactNumbers {
+			sb.WriteString(exactNumber(string(raw)))
+		} else {
+			sb.WriteString(canonicalNumber(string(raw)))
+		}
+
+	default:
+		sb.Write(raw)
+	}
+}
+
+// Writes a number literal in one form per value: float64's shortest form, except that from 2^53 up, where float64 cannot tell neighbouring integers apart, and past float64's range, it is written exactly as exactNumber writes it, however it is spelled
+func canonicalNumber(lit string) string {
+	f, err := strconv.ParseFloat(lit, 64)
+
+	if err != nil || math.Abs(f) >= 1<<53 {
+		return exactNumber(lit)
+	}
+
+	if f == 0 {
+		// -0 equals 0
+		return "0"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Writes a number literal in one form per exact decimal value, for WithExactNumbers; literals whose exponents overflow are kept as written
+func exactNumber(lit string) string {
+	d, ok := parseDecimal(lit)
+	if !ok {
+		return lit
+	}
+
+	return d.String()
+}
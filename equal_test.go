@@ -0,0 +1,47 @@
+package jsondescriber
+
+import "testing"
+
+// Numbers are equal when they denote the same value, however they are spelled and however large they are
+func TestEqualNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1", "1.0", true},
+		{"1", "1e0", true},
+		{"0", "-0", true},
+		{"0.1", "1e-1", true},
+		{"1e20", "100000000000000000000", true},
+		{"1E+20", "100000000000000000000.000", true},
+		{"9007199254740992", "9007199254740992.0", true},
+		{"9007199254740992", "9.007199254740992e15", true},
+		{"-9007199254740992", "-9007199254740992.0", true},
+		{"1e400", "10e399", true},
+		{"9007199254740993", "9007199254740992", false},
+		{"9007199254740993", "9007199254740993.0", true},
+		{"100000000000000000001", "1e20", false},
+		{"1e400", "1e401", false},
+		{"1", "2", false},
+	}
+
+	for _, tt := range tests {
+		a, err := UnmarshalObject([]byte(`{"n":` + tt.a + `}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := UnmarshalObject([]byte(`{"n":` + tt.b + `}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%s equal to %s: got %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+
+		if got := b.Equal(a); got != tt.want {
+			t.Errorf("%s equal to %s: got %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
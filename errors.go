@@ -0,0 +1,88 @@
+package jsondescriber
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returned, possibly wrapped, when input is not syntactically valid JSON
+//
+// It is spelled Json, not JSON, like every other exported name in the package, such as JsonDescription and JsonType.
+var ErrInvalidJson = errors.New("not valid json")
+
+// Returned, possibly wrapped, when input nests objects and arrays deeper than can be processed or than WithMaxDepth allows
+var ErrTooDeep = errors.New("json nested too deeply")
+
+// Returned, possibly wrapped, when input is longer or has more members than WithMaxBytes or WithMaxMembers allows
+var ErrTooLarge = errors.New("json too large")
+
+// Returned when input is valid JSON of a different type than the operation requires; match with errors.As
+type ErrUnexpectedType struct {
+	Want JsonType
+	Got  JsonType
+}
+
+func (e *ErrUnexpectedType) Error() string {
+	return fmt.Sprintf("given []byte is %s, expected %s", e.Got, e.Want)
+}
+
+// Reports a member of the top-level container that WithLenient passed over because it was malformed
+type MemberError struct {
+	// The member's position in its container, counting skipped members
+	Index int
+	// The member's key, if it belonged to an object and its key could be read
+	Key string
+	// Byte offset at which the member began
+	Offset int64
+	// Why the member could not be scanned; matches ErrInvalidJson
+	Err error
+}
+
+func (e *MemberError) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("member %d (%s) at offset %d: %v", e.Index, SafeKey(e.Key), e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("member %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *MemberError) Unwrap() error {
+	return e.Err
+}
+
+// The nesting limit of encoding/json, past which it rejects otherwise valid documents
+const stdlibMaxDepth = 10000
+
+// Returns the deepest nesting of objects and arrays in data, ignoring brackets inside strings
+func nestingDepth(data []byte) int {
+	var (
+		depth, max int
+		inString   bool
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			if depth++; depth > max {
+				max = depth
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return max
+}
@@ -0,0 +1,137 @@
+package jsondescriber
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// Where WalkTokens is in a document when it calls a TokenHandler
+type TokenEvent struct {
+	// How many containers enclose the event: 0 for the top-level value, including its own start and end
+	Depth int
+	// The location of the container, key, or value, in the style set by WithPathStyle
+	Path string
+	// The member's key, for Key events
+	Key string
+	// The type of the value, for Value events and the start and end of containers
+	Type JsonType
+	// The value as json.Decoder reads it with UseNumber: a string, json.Number, bool, or nil; set for Value events only
+	Value json.Token
+}
+
+// The callbacks WalkTokens calls as it reads a document; any left nil are not called
+//
+// Returning an error from a callback stops the walk with that error.
+type TokenHandler struct {
+	ObjectStart func(TokenEvent) error
+	ObjectEnd   func(TokenEvent) error
+	ArrayStart  func(TokenEvent) error
+	ArrayEnd    func(TokenEvent) error
+	// Called with each object key before its value
+	Key func(TokenEvent) error
+	// Called with each string, number, boolean, and null
+	Value func(TokenEvent) error
+}
+
+// Reads a document from r token by token, calling h for the start and end of each container, each key, and each scalar value, in document order, for building analyses of documents too large to hold whole
+//
+// This is the token reader DescribeReader and DiffStream are built on. Keys and values are handed over decoded, so escapes in the input are already resolved. ctx is checked as tokens are read. Honors WithPathStyle, WithMaxDepth, WithMaxBytes, WithMaxMembers, and WithDecompression.
+func WalkTokens(ctx context.Context, r io.Reader, h *TokenHandler, opts ...Option) error {
+	cfg := newConfig(opts)
+
+	r, err := cfg.decompress(r)
+	if err != nil {
+		return err
+	}
+
+	w := newTokenWalker(ctx, r, cfg)
+
+	tok, err := w.token()
+	if err != nil {
+		return err
+	}
+
+	if err = h.walk(w, tok, "", 0); err != nil {
+		return err
+	}
+
+	return w.finish()
+}
+
+// Calls h for the value tok begins, found at path, and everything within it
+func (h *TokenHandler) walk(w *tokenWalker, tok json.Token, path string, depth int) error {
+	var (
+		typ   = tokenType(tok)
+		event = TokenEvent{Depth: depth, Path: path, Type: typ}
+	)
+
+	switch typ {
+	case Object:
+		if err := h.call(h.ObjectStart, event); err != nil {
+			return err
+		}
+
+		for {
+			tok, err := w.token()
+			if err != nil {
+				return err
+			}
+
+			if tok == json.Delim('}') {
+				break
+			}
+
+			key, _ := tok.(string)
+			child := joinKey(w.cfg.pathStyle, path, key)
+
+			if err = h.call(h.Key, TokenEvent{Depth: depth + 1, Path: child, Key: key}); err != nil {
+				return err
+			}
+
+			if tok, err = w.token(); err != nil {
+				return err
+			}
+
+			if err = h.walk(w, tok, child, depth+1); err != nil {
+				return err
+			}
+		}
+
+		return h.call(h.ObjectEnd, event)
+
+	case Array:
+		if err := h.call(h.ArrayStart, event); err != nil {
+			return err
+		}
+
+		for i := 0; ; i++ {
+			tok, err := w.token()
+			if err != nil {
+				return err
+			}
+
+			if tok == json.Delim(']') {
+				break
+			}
+
+			if err = h.walk(w, tok, joinIndex(w.cfg.pathStyle, path, i), depth+1); err != nil {
+				return err
+			}
+		}
+
+		return h.call(h.ArrayEnd, event)
+	}
+
+	event.Value = tok
+	return h.call(h.Value, event)
+}
+
+// Calls fn with event unless fn is nil
+func (h *TokenHandler) call(fn func(TokenEvent) error, event TokenEvent) error {
+	if fn == nil {
+		return nil
+	}
+
+	return fn(event)
+}
@@ -0,0 +1,73 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Hashes the structure of a document into a stable hex-encoded SHA-256 digest, so documents can be bucketed by shape without keeping them
+//
+// The structure is every object's keys and every member's type; key order, whitespace, and values do not matter, true and false count as one boolean type, and an array is characterized by the distinct structures of its elements, so [1] and [2, 3] share a fingerprint. Under WithFingerprintValues values count too, compared as RawObject.Equal compares them, and element order counts unless WithUnorderedArrays is given. Honors WithFingerprintValues, WithUnorderedArrays, and WithExactNumbers.
+func Fingerprint(data []byte, opts ...Option) (string, error) {
+	var (
+		cfg = newConfig(opts)
+		sig string
+	)
+
+	data = bytes.TrimSpace(data)
+
+	if _, err := TypeOf(data); err != nil {
+		return "", err
+	}
+
+	if cfg.fingerprintValues {
+		sig = cfg.canonical(data)
+	} else {
+		sig = structure(data)
+	}
+
+	sum := sha256.Sum256([]byte(sig))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Encodes the keys and types of a valid value, leaving out its scalars' values
+func structure(raw json.RawMessage) string {
+	typ, _ := JsonTypeOf(raw)
+
+	switch typ {
+	case Object:
+		obj := make(RawObject)
+		json.Unmarshal(raw, &obj)
+
+		members := make([]string, 0, len(obj))
+		for _, k := range sortedKeys(obj) {
+			members = append(members, strconv.Quote(k)+":"+structure(obj[k]))
+		}
+		return "{" + strings.Join(members, ",") + "}"
+
+	case Array:
+		arr := make(RawArray, 0)
+		json.Unmarshal(raw, &arr)
+
+		seen := make(map[string]bool)
+		elems := make([]string, 0)
+		for i := range arr {
+			if sig := structure(arr[i]); !seen[sig] {
+				seen[sig] = true
+				elems = append(elems, sig)
+			}
+		}
+		sort.Strings(elems)
+		return "[" + strings.Join(elems, ",") + "]"
+
+	case True, False:
+		return "boolean"
+	}
+
+	return typ.String()
+}
@@ -0,0 +1,278 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Converts nested JSON into a single-level map from each leaf's path to its raw value
+//
+// Leaves are scalars and empty containers, so no structure is lost. A scalar document flattens to a single entry under "". Honors WithPathStyle.
+func Flatten(data []byte, opts ...Option) (map[string]json.RawMessage, error) {
+	var (
+		cfg  = newConfig(opts)
+		flat = make(map[string]json.RawMessage)
+	)
+
+	data = bytes.TrimSpace(data)
+
+	if _, err := TypeOf(data); err != nil {
+		return flat, err
+	}
+
+	err := flatten(data, "", cfg.pathStyle, flat)
+	return flat, err
+}
+
+func flatten(raw json.RawMessage, path string, style PathStyle, flat map[string]json.RawMessage) error {
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		obj := make(RawObject)
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+
+		if len(obj) == 0 {
+			break
+		}
+
+		for k := range obj {
+			if err := flatten(obj[k], joinKey(style, path, k), style, flat); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+
+		if len(arr) == 0 {
+			break
+		}
+
+		for i := range arr {
+			if err := flatten(arr[i], joinIndex(style, path, i), style, flat); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	flat[path] = raw
+	return nil
+}
+
+// One location in a document being rebuilt by Unflatten
+type flatNode struct {
+	value    json.RawMessage
+	keys     []string
+	children map[string]*flatNode
+	indexed  bool // addressed as [i] in a dotted path
+	named    bool // addressed by key in a dotted path
+}
+
+// One step of a parsed path
+type pathStep struct {
+	token   string
+	indexed bool
+}
+
+// Rebuilds nested JSON from a map of paths to leaf values, the inverse of Flatten
+//
+// With DottedPath, bracketed indices make arrays. With PointerPath, a level whose tokens are all array indices becomes an array, so an object keyed only by "0", "1", ... does not round-trip; use DottedPath where that matters. Indices must be contiguous from zero. Honors WithPathStyle.
+func Unflatten(flat map[string]json.RawMessage, opts ...Option) ([]byte, error) {
+	var (
+		cfg  = newConfig(opts)
+		root = &flatNode{children: make(map[string]*flatNode)}
+	)
+
+	for _, path := range sortedKeys(flat) {
+		var (
+			steps []pathStep
+			err   error
+		)
+
+		if cfg.pathStyle == DottedPath {
+			steps, err = parseDotted(path)
+		} else {
+			var tokens []string
+			tokens, err = parsePointer(path)
+			for _, tok := range tokens {
+				steps = append(steps, pathStep{token: tok})
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !json.Valid(flat[path]) {
+			return nil, fmt.Errorf("value at %q: %w", path, ErrInvalidJson)
+		}
+
+		if err = root.insert(steps, flat[path], path); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+
+	if err := root.write(&out, cfg.pathStyle); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Places value at the end of steps, creating nodes along the way
+func (n *flatNode) insert(steps []pathStep, value json.RawMessage, path string) error {
+	for _, step := range steps {
+		if n.value != nil {
+			return fmt.Errorf("path %q descends into a leaf value", path)
+		}
+
+		if step.indexed {
+			n.indexed = true
+		} else {
+			n.named = true
+		}
+
+		if n.indexed && n.named {
+			return fmt.Errorf("path %q mixes keys and indices at one level", path)
+		}
+
+		child, ok := n.children[step.token]
+
+		if !ok {
+			child = &flatNode{children: make(map[string]*flatNode)}
+			n.children[step.token] = child
+			n.keys = append(n.keys, step.token)
+		}
+
+		n = child
+	}
+
+	if n.value != nil || len(n.children) > 0 {
+		return fmt.Errorf("path %q is given more than one value", path)
+	}
+
+	n.value = value
+	return nil
+}
+
+// Serializes the node, deciding per level whether it is an array or an object
+func (n *flatNode) write(out *bytes.Buffer, style PathStyle) error {
+	if n.value != nil {
+		return json.Compact(out, n.value)
+	}
+
+	isArray := n.indexed
+
+	if style == PointerPath && len(n.keys) > 0 {
+		isArray = true
+		for _, k := range n.keys {
+			if _, err := arrayIndex(k, len(n.keys), false); err != nil {
+				isArray = false
+				break
+			}
+		}
+	}
+
+	if isArray {
+		out.WriteByte('[')
+		for i := 0; i < len(n.keys); i++ {
+			child, ok := n.children[strconv.Itoa(i)]
+			if !ok {
+				return fmt.Errorf("array is missing index %d", i)
+			}
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := child.write(out, style); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+	}
+
+	out.WriteByte('{')
+	for i, k := range n.keys {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		enc, _ := json.Marshal(k)
+		out.Write(enc)
+		out.WriteByte(':')
+		if err := n.children[k].write(out, style); err != nil {
+			return err
+		}
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+// Parses a dotted path such as `user.tags[0]` or `["a.b"].c` into steps
+func parseDotted(path string) ([]pathStep, error) {
+	var (
+		steps = make([]pathStep, 0)
+		i     = 0
+	)
+
+	for i < len(path) {
+		switch {
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+
+			if i+1 < len(path) && path[i+1] == '"' {
+				// Quoted keys may themselves contain brackets, so scan the string literal
+				lit, err := strconv.QuotedPrefix(path[i+1:])
+				if err != nil || i+1+len(lit) >= len(path) || path[i+1+len(lit)] != ']' {
+					return nil, fmt.Errorf("invalid quoted key in path %q", path)
+				}
+				key, _ := strconv.Unquote(lit)
+				steps = append(steps, pathStep{token: key})
+				i += len(lit) + 2
+				break
+			}
+
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in path %q", path)
+			}
+
+			idx := path[i+1 : i+end]
+			if _, err := arrayIndex(idx, int(^uint(0)>>1), false); err != nil {
+				return nil, fmt.Errorf("path %q: %w", path, err)
+			}
+			steps = append(steps, pathStep{token: idx, indexed: true})
+			i += end + 1
+
+		case path[i] == '.':
+			if i == 0 || i+1 == len(path) || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, fmt.Errorf("misplaced . in path %q", path)
+			}
+			i++
+
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if i > 0 && path[i-1] != '.' {
+				return nil, fmt.Errorf("missing . before key in path %q", path)
+			}
+			steps = append(steps, pathStep{token: path[i : i+end]})
+			i += end
+		}
+	}
+
+	return steps, nil
+}
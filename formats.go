@@ -0,0 +1,83 @@
+package jsondescriber
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A well-known kind of string recognized under WithStringFormats
+type StringFormat int
+
+const (
+	// No recognized format
+	NoFormat StringFormat = iota
+	// RFC 3339 full-date, e.g. "2024-02-29"
+	FormatDate
+	// RFC 3339 date-time, e.g. "2024-02-29T12:00:00Z"
+	FormatDateTime
+	// RFC 4122 textual form, e.g. "123e4567-e89b-12d3-a456-426614174000"
+	FormatUUID
+	// A bare address of the form local@domain.tld
+	FormatEmail
+	// An absolute URL with a scheme and host
+	FormatURL
+)
+
+var stringFormatNames = [...]string{
+	NoFormat:       "",
+	FormatDate:     "ISO-8601 date",
+	FormatDateTime: "ISO-8601 timestamp",
+	FormatUUID:     "UUID",
+	FormatEmail:    "email address",
+	FormatURL:      "URL",
+}
+
+// Returns the name used in descriptions, e.g. "UUID"
+func (f StringFormat) String() string {
+	if f < 0 || int(f) >= len(stringFormatNames) {
+		return ""
+	}
+
+	return stringFormatNames[f]
+}
+
+var (
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailPattern = regexp.MustCompile(`^[^@\s"]+@[^@\s.]+(\.[^@\s.]+)+$`)
+)
+
+// Recognizes the format of a decoded string, checking the cheapest and most specific formats first
+func DetectFormat(s string) StringFormat {
+	switch {
+	case len(s) == 36 && uuidPattern.MatchString(s):
+		return FormatUUID
+	case len(s) == 10 && isTime("2006-01-02", s):
+		return FormatDate
+	case len(s) > 10 && s[4] == '-' && isTime(time.RFC3339Nano, s):
+		return FormatDateTime
+	case strings.IndexByte(s, '@') > 0 && emailPattern.MatchString(s):
+		return FormatEmail
+	case strings.Contains(s, "://") && !strings.ContainsAny(s, " \t\r\n"):
+		if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+			return FormatURL
+		}
+	}
+
+	return NoFormat
+}
+
+func isTime(layout, s string) bool {
+	_, err := time.Parse(layout, s)
+	return err == nil
+}
+
+// Pluralizes a format name for counts, e.g. "2 email addresses"
+func pluralFormat(name string) string {
+	if strings.HasSuffix(name, "s") {
+		return name + "es"
+	}
+
+	return name + "s"
+}
@@ -0,0 +1,188 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// The data a template given to WithTemplate is executed with
+type FriendlyData struct {
+	// The element type, e.g. "object" or "string"
+	Element string
+	// Member counts such as "2 strings", in display order; empty for scalars and empty containers
+	Members []string
+	// Members joined into a grammatical English list
+	List string
+	// Top-level object keys in the order first seen, if recorded under WithKeyNames
+	Keys []string
+}
+
+// Selects how much Friendly says about a description
+type Verbosity int
+
+const (
+	// Only the type and how many members it has, e.g. "object, 5 members"
+	Terse Verbosity = iota
+	// A sentence listing member types, e.g. "an object with 3 numbers and 2 strings"; the default
+	Normal
+	// Normal, plus every level of nested descriptions regardless of WithNesting, and how a sample was taken
+	Verbose
+)
+
+// Summarizes the description as its type and total member count, e.g. "object, 5 members" or "array, 1 element"
+func (jd *JsonDescription) terse() string {
+	var total uint
+
+	for _, n := range jd.Members {
+		total += n
+	}
+
+	noun := "member"
+	if jd.Element == "array" {
+		noun = "element"
+	}
+	if total != 1 {
+		noun += "s"
+	}
+
+	switch jd.Element {
+	case "object", "array":
+		return fmt.Sprintf("%s, %d %s", jd.Element, total, noun)
+	}
+
+	return jd.Element
+}
+
+// Explains how a sample was taken, e.g. "sampled from the first 100 elements"
+func (s *Sample) explain() string {
+	if s.Random {
+		return fmt.Sprintf("sampled %d elements at random, seed %d", s.Size, s.Seed)
+	}
+
+	return fmt.Sprintf("sampled from the first %d elements", s.Size)
+}
+
+// Says how many malformed members were left out, e.g. "2 malformed elements skipped"
+func (jd *JsonDescription) skipped(cfg *config) string {
+	noun := "member"
+	if jd.Element == "array" {
+		noun = "element"
+	}
+	if len(jd.Skipped) != 1 {
+		noun += "s"
+	}
+
+	return fmt.Sprintf("%s malformed %s skipped", cfg.count(uint(len(jd.Skipped))), noun)
+}
+
+// Words for the counts WithCountWords spells out
+var countWords = [...]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// Writes a count as digits, or under WithCountWords as a word when it is below ten
+func (c *config) count(n uint) string {
+	if c.countWords && n < uint(len(countWords)) {
+		return countWords[n]
+	}
+
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+// Rolls up members or keys left out by WithTopN, e.g. "37 others"
+func (c *config) others(n uint) string {
+	if n == 1 {
+		return c.count(n) + " other"
+	}
+
+	return c.count(n) + " others"
+}
+
+// A member type and how many times it occurs
+type TypeCount struct {
+	Type  string
+	Count uint
+}
+
+// ByCount orders member types by count, largest first, then by type name; it is Friendly's default
+func ByCount(a, b TypeCount) bool {
+	if a.Count != b.Count {
+		return a.Count > b.Count
+	}
+
+	return a.Type < b.Type
+}
+
+// ByTypeName orders member types alphabetically by type name
+func ByTypeName(a, b TypeCount) bool {
+	return a.Type < b.Type
+}
+
+// Lists counts in the order given by less, breaking ties by type name
+func sortCounts(counts map[string]uint, less func(a, b TypeCount) bool) []TypeCount {
+	list := make([]TypeCount, 0, len(counts))
+
+	for _, k := range sortedKeys(counts) {
+		list = append(list, TypeCount{Type: k, Count: counts[k]})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return less(list[i], list[j])
+	})
+
+	return list
+}
+
+// Oxfordizes a list of phrases: "a", "a and b", or "a, b, and c"
+//
+// WithConjunction replaces "and", and WithOxfordComma(false) drops the comma before it.
+func (c *config) joinList(items []string) string {
+	var (
+		count = len(items)
+		last  = " " + c.conjunction + " "
+	)
+
+	if c.oxfordComma {
+		last = "," + last
+	}
+
+	if count == 1 {
+		return items[0]
+	} else if count == 2 {
+		return strings.Join(items, " "+c.conjunction+" ")
+	} else if count > 2 {
+		return fmt.Sprintf(
+			"%s%s%s",
+			strings.Join(items[:count-1], ", "),
+			last,
+			items[count-1],
+		)
+	}
+
+	return ""
+}
+
+// Renders the description through tmpl, preferring a sub-template named for the element type; reports false if execution fails
+func (jd *JsonDescription) execTemplate(tmpl *template.Template, cfg *config, depth int) (string, bool) {
+	var (
+		sb   strings.Builder
+		inv  = descElem(jd.Members, jd.Formats, jd.Nested, cfg, depth)
+		data = FriendlyData{
+			Element: jd.Element,
+			Members: inv,
+			List:    cfg.joinList(inv),
+			Keys:    jd.Keys,
+		}
+	)
+
+	if named := tmpl.Lookup(jd.Element); named != nil {
+		tmpl = named
+	}
+
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", false
+	}
+
+	return sb.String(), true
+}
@@ -0,0 +1,104 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// One file described by DescribeFS
+type FileDescription struct {
+	// Slash-separated path within the file system
+	Path string
+	// Describes the file's document; nil if it could not be read or described
+	Description *JsonDescription
+	// Why the file could not be read or described, if it could not
+	Err error
+}
+
+// The files described by DescribeFS, with a summary across all of them
+type FSDescription struct {
+	// Every matching file, in lexical order of path
+	Files []FileDescription
+	// How many files held each type of document, keyed by type name
+	Elements map[string]uint
+	// The merged shape of every document that could be described
+	Shape *Shape
+	// How many files could not be read or described
+	Errors uint
+}
+
+// Describes every file in fsys whose path matches glob, as path.Match, for profiling a folder of fixtures in one call
+//
+// A pattern without a slash, such as "*.json", matches file names at any depth; one with a slash matches whole paths from the root of fsys. A file that cannot be read or described is reported with its error rather than stopping the walk, so the only errors returned are for a malformed pattern or an unreadable root. Honors the options Describe honors.
+func DescribeFS(fsys fs.FS, glob string, opts ...Option) (*FSDescription, error) {
+	var (
+		agg  = NewAggregator()
+		desc = &FSDescription{Elements: make(map[string]uint)}
+		base = !strings.Contains(glob, "/")
+	)
+
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("pattern %q: %w", glob, err)
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == "." {
+				return err
+			}
+			desc.add(FileDescription{Path: p, Err: err})
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		name := p
+		if base {
+			name = d.Name()
+		}
+
+		if ok, _ := path.Match(glob, name); !ok {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			desc.add(FileDescription{Path: p, Err: err})
+			return nil
+		}
+
+		jd, err := Describe(data, opts...)
+		if err != nil {
+			desc.add(FileDescription{Path: p, Err: err})
+			return nil
+		}
+
+		agg.Add(data)
+		desc.add(FileDescription{Path: p, Description: jd})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	desc.Shape = agg.Shape()
+	return desc, nil
+}
+
+// Records a file, counting it in the summary
+func (d *FSDescription) add(f FileDescription) {
+	d.Files = append(d.Files, f)
+
+	if f.Err != nil {
+		d.Errors++
+		return
+	}
+
+	d.Elements[f.Description.Element]++
+}
@@ -0,0 +1,208 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Fetches the string member at key, decoded
+//
+// A missing key, a value of another type, or an invalid value is reported with the member's JSON Pointer; a mismatch wraps an *ErrUnexpectedType.
+func (o *RawObject) GetString(key string) (string, error) {
+	raw, ptr, err := o.member(key)
+	if err != nil {
+		return "", err
+	}
+
+	return getString(raw, ptr)
+}
+
+// Fetches the number member at key as an int64; it must be written as an integer and fit
+func (o *RawObject) GetInt(key string) (int64, error) {
+	raw, ptr, err := o.member(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return getInt(raw, ptr)
+}
+
+// Fetches the true or false member at key; a mismatch's *ErrUnexpectedType wants True
+func (o *RawObject) GetBool(key string) (bool, error) {
+	raw, ptr, err := o.member(key)
+	if err != nil {
+		return false, err
+	}
+
+	return getBool(raw, ptr)
+}
+
+// Fetches the object member at key
+func (o *RawObject) GetObject(key string) (*RawObject, error) {
+	raw, ptr, err := o.member(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return getObject(raw, ptr)
+}
+
+// Fetches the array member at key
+func (o *RawObject) GetArray(key string) (*RawArray, error) {
+	raw, ptr, err := o.member(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return getArray(raw, ptr)
+}
+
+// Fetches the string element at index i, decoded
+//
+// An index out of range, a value of another type, or an invalid value is reported with the element's JSON Pointer; a mismatch wraps an *ErrUnexpectedType.
+func (a *RawArray) GetString(i int) (string, error) {
+	raw, ptr, err := a.element(i)
+	if err != nil {
+		return "", err
+	}
+
+	return getString(raw, ptr)
+}
+
+// Fetches the number element at index i as an int64; it must be written as an integer and fit
+func (a *RawArray) GetInt(i int) (int64, error) {
+	raw, ptr, err := a.element(i)
+	if err != nil {
+		return 0, err
+	}
+
+	return getInt(raw, ptr)
+}
+
+// Fetches the true or false element at index i; a mismatch's *ErrUnexpectedType wants True
+func (a *RawArray) GetBool(i int) (bool, error) {
+	raw, ptr, err := a.element(i)
+	if err != nil {
+		return false, err
+	}
+
+	return getBool(raw, ptr)
+}
+
+// Fetches the object element at index i
+func (a *RawArray) GetObject(i int) (*RawObject, error) {
+	raw, ptr, err := a.element(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return getObject(raw, ptr)
+}
+
+// Fetches the array element at index i
+func (a *RawArray) GetArray(i int) (*RawArray, error) {
+	raw, ptr, err := a.element(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return getArray(raw, ptr)
+}
+
+// Looks up a member, returning it with its JSON Pointer
+func (o *RawObject) member(key string) (json.RawMessage, string, error) {
+	ptr := joinKey(PointerPath, "", key)
+
+	raw, ok := (*o)[key]
+	if !ok {
+		return nil, ptr, fmt.Errorf("no member at %s", ptr)
+	}
+
+	return raw, ptr, nil
+}
+
+// Looks up an element, returning it with its JSON Pointer
+func (a *RawArray) element(i int) (json.RawMessage, string, error) {
+	ptr := joinIndex(PointerPath, "", i)
+
+	if i < 0 || i >= len(*a) {
+		return nil, ptr, fmt.Errorf("array index %d out of range", i)
+	}
+
+	return (*a)[i], ptr, nil
+}
+
+// Checks that the value at ptr is of one of the types wanted, reporting the first if not
+func expectType(raw json.RawMessage, ptr string, want ...JsonType) error {
+	got, err := JsonTypeOf(bytes.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("%s: %w", ptr, err)
+	}
+
+	for _, t := range want {
+		if got == t {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: %w", ptr, &ErrUnexpectedType{Want: want[0], Got: got})
+}
+
+func getString(raw json.RawMessage, ptr string) (string, error) {
+	if err := expectType(raw, ptr, String); err != nil {
+		return "", err
+	}
+
+	return unquote(bytes.TrimSpace(raw)), nil
+}
+
+func getInt(raw json.RawMessage, ptr string) (int64, error) {
+	if err := expectType(raw, ptr, Number); err != nil {
+		return 0, err
+	}
+
+	lit := string(bytes.TrimSpace(raw))
+
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s is not an int64", ptr, lit)
+	}
+
+	return n, nil
+}
+
+func getBool(raw json.RawMessage, ptr string) (bool, error) {
+	if err := expectType(raw, ptr, True, False); err != nil {
+		return false, err
+	}
+
+	return raw[bytes.IndexAny(raw, "tf")] == 't', nil
+}
+
+func getObject(raw json.RawMessage, ptr string) (*RawObject, error) {
+	if err := expectType(raw, ptr, Object); err != nil {
+		return nil, err
+	}
+
+	obj := make(RawObject)
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("%s: %w", ptr, err)
+	}
+
+	return &obj, nil
+}
+
+func getArray(raw json.RawMessage, ptr string) (*RawArray, error) {
+	if err := expectType(raw, ptr, Array); err != nil {
+		return nil, err
+	}
+
+	arr := make(RawArray, 0)
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return nil, fmt.Errorf("%s: %w", ptr, err)
+	}
+
+	return &arr, nil
+}
@@ -1,3 +1,3 @@
 module github.com/andyborne/jsondescriber
 
-go 1.19
+go 1.23
@@ -0,0 +1,295 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Emits a Graphviz digraph of a document: one node per container or leaf, labeled by type, with edges labeled by key or index
+func ToDOT(data []byte) (string, error) {
+	var (
+		sb   strings.Builder
+		next int
+	)
+
+	tree, err := structureTree(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	sb.WriteString("digraph json {\n")
+	sb.WriteString("\tnode [fontname=\"monospace\"];\n")
+	sb.WriteString("\tedge [fontname=\"monospace\"];\n")
+
+	var walk func(n *ReportNode) int
+	walk = func(n *ReportNode) int {
+		id := next
+		next++
+
+		shape := "ellipse"
+		if n.Type == "object" || n.Type == "array" {
+			shape = "box"
+		}
+
+		fmt.Fprintf(&sb, "\tn%d [label=%s, shape=%s];\n", id, dotQuote(n.Type), shape)
+
+		for _, c := range n.Children {
+			cid := walk(c)
+			fmt.Fprintf(&sb, "\tn%d -> n%d [label=%s];\n", id, cid, dotQuote(c.Name))
+		}
+
+		return id
+	}
+
+	walk(tree)
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// Validates a raw document and builds its structure tree
+func structureTree(data []byte) (*ReportNode, error) {
+	data = bytes.TrimSpace(data)
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	return reportTree("", data)
+}
+
+// Quotes s as a DOT string literal
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Emits a Mermaid flowchart of a document, mirroring ToDOT
+func ToMermaidFlowchart(data []byte) (string, error) {
+	var (
+		sb   strings.Builder
+		next int
+	)
+
+	tree, err := structureTree(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	sb.WriteString("flowchart LR\n")
+
+	var walk func(n *ReportNode) int
+	walk = func(n *ReportNode) int {
+		id := next
+		next++
+
+		if n.Type == "object" || n.Type == "array" {
+			fmt.Fprintf(&sb, "\tn%d[%s]\n", id, mermaidQuote(n.Type))
+		} else {
+			fmt.Fprintf(&sb, "\tn%d(%s)\n", id, mermaidQuote(n.Type))
+		}
+
+		for _, c := range n.Children {
+			cid := walk(c)
+			fmt.Fprintf(&sb, "\tn%d -->|%s| n%d\n", id, mermaidQuote(c.Name), cid)
+		}
+
+		return id
+	}
+
+	walk(tree)
+	return sb.String(), nil
+}
+
+// A class in a Mermaid classDiagram, built from one or more objects
+type mermaidClass struct {
+	name   string
+	fields []KeyType
+	index  map[string]int
+}
+
+// Accumulates the classes and relations of a classDiagram in first-seen order
+type mermaidDiagram struct {
+	classes   []*mermaidClass
+	byName    map[string]*mermaidClass
+	relations []string
+	related   map[string]bool
+}
+
+// Emits a Mermaid classDiagram with a class per nested object; objects within an array are merged into a single Item class
+func ToMermaidClassDiagram(data []byte) (string, error) {
+	var (
+		sb strings.Builder
+		md = &mermaidDiagram{
+			byName:  make(map[string]*mermaidClass),
+			related: make(map[string]bool),
+		}
+	)
+
+	data = bytes.TrimSpace(data)
+	typ, err := TypeOf(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch *typ {
+	case "object":
+		err = md.object("Root", data)
+	case "array":
+		err = md.items("", "RootItem", "", data)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	sb.WriteString("classDiagram\n")
+
+	for _, c := range md.classes {
+		fmt.Fprintf(&sb, "\tclass %s {\n", c.name)
+		for _, f := range c.fields {
+			fmt.Fprintf(&sb, "\t\t%s %s\n", f.Type, mermaidText(f.Key))
+		}
+		sb.WriteString("\t}\n")
+	}
+
+	for _, rel := range md.relations {
+		sb.WriteString(rel)
+	}
+
+	return sb.String(), nil
+}
+
+// Returns the named class, creating it on first use
+func (md *mermaidDiagram) class(name string) *mermaidClass {
+	if c, ok := md.byName[name]; ok {
+		return c
+	}
+
+	c := &mermaidClass{name: name, index: make(map[string]int)}
+	md.classes = append(md.classes, c)
+	md.byName[name] = c
+	return c
+}
+
+// Records a relation once, however many objects imply it
+func (md *mermaidDiagram) relate(from, to, label string) {
+	rel := fmt.Sprintf("\t%s --> %s : %s\n", from, to, mermaidText(label))
+
+	if !md.related[rel] {
+		md.related[rel] = true
+		md.relations = append(md.relations, rel)
+	}
+}
+
+// Adds an object's members to the named class and descends into nested objects and arrays
+func (md *mermaidDiagram) object(name string, raw []byte) error {
+	c := md.class(name)
+	members, err := orderedMembers(raw)
+
+	if err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		mt, _ := TypeOf(m.Value)
+		c.field(m.Key, *mt)
+		child := name + "_" + mermaidIdent(m.Key)
+
+		switch *mt {
+		case "object":
+			md.relate(name, child, m.Key)
+			err = md.object(child, m.Value)
+		case "array":
+			err = md.items(name, child+"Item", m.Key+"[]", m.Value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Merges the objects within an array into one class related to parent
+func (md *mermaidDiagram) items(parent, name, label string, raw []byte) error {
+	arr := make(RawArray, 0)
+
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return err
+	}
+
+	for i := range arr {
+		if et, _ := TypeOf(arr[i]); *et == "object" {
+			if parent != "" {
+				md.relate(parent, name, label)
+			}
+			if err := md.object(name, arr[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Adds a field to the class, joining types with "|" when merged objects disagree
+func (c *mermaidClass) field(key, typ string) {
+	i, ok := c.index[key]
+
+	if !ok {
+		c.index[key] = len(c.fields)
+		c.fields = append(c.fields, KeyType{Key: key, Type: typ})
+		return
+	}
+
+	for _, t := range strings.Split(c.fields[i].Type, "|") {
+		if t == typ {
+			return
+		}
+	}
+
+	c.fields[i].Type += "|" + typ
+}
+
+// Quotes s as a Mermaid node or edge label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// Reduces s to characters Mermaid accepts in class member and relation text
+func mermaidText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("{}()<>~:;\"`#", r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if s == "" {
+		return "_"
+	}
+
+	return s
+}
+
+// Reduces s to a valid Mermaid class identifier
+func mermaidIdent(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 128 && (r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, s)
+
+	if s == "" {
+		return "_"
+	}
+
+	return s
+}
@@ -0,0 +1,104 @@
+package jsondescriber
+
+import (
+	"bytes"
+)
+
+// Recognizes a top-level form that is not plain JSON, returning the type it stands for and the JSON it wraps, if any
+//
+// A rule that wraps JSON, such as a byte-order mark before a document, returns the JSON as inner, and its type is then that of inner unless typ says otherwise; a rule for a value with no JSON form, such as an extension literal, returns inner nil.
+type Heuristic func(data []byte) (typ JsonType, inner []byte, ok bool)
+
+// A registry of the rules for recognizing top-level values and naming their types, for extending TypeOf without forking
+//
+// Rules added are tried in order before the built-in ones, which recognize JSON by its first character. The zero value is not ready to use; start from NewHeuristics. A Heuristics is not safe to change while in use.
+type Heuristics struct {
+	rules []Heuristic
+	names map[JsonType]string
+}
+
+// Constructor for Heuristics recognizing plain JSON only, with the usual type names
+func NewHeuristics() *Heuristics {
+	return &Heuristics{names: make(map[JsonType]string)}
+}
+
+// Adds a rule, tried after those added before it
+func (h *Heuristics) Add(rule Heuristic) *Heuristics {
+	h.rules = append(h.rules, rule)
+	return h
+}
+
+// Reports type t by name from TypeOf and Name, e.g. "boolean" for True and False alike
+func (h *Heuristics) Rename(t JsonType, name string) *Heuristics {
+	h.names[t] = name
+	return h
+}
+
+// Names type t as renamed, or as JsonType.String does
+func (h *Heuristics) Name(t JsonType) string {
+	if name, ok := h.names[t]; ok {
+		return name
+	}
+
+	return t.String()
+}
+
+// Like the package-level JsonTypeOf, but trying the registered rules first
+func (h *Heuristics) JsonTypeOf(data []byte) (JsonType, error) {
+	typ, _, err := h.recognize(data)
+	return typ, err
+}
+
+// Like the package-level TypeOf, but trying the registered rules first and naming types as renamed
+func (h *Heuristics) TypeOf(data []byte) (*string, error) {
+	var name string
+
+	typ, err := h.JsonTypeOf(data)
+
+	if typ != Undefined {
+		name = h.Name(typ)
+	}
+
+	return &name, err
+}
+
+// Finds the type of data by the first rule that claims it, or else as plain JSON, returning the JSON to describe in its place, if any
+func (h *Heuristics) recognize(data []byte) (JsonType, []byte, error) {
+	for _, rule := range h.rules {
+		typ, inner, ok := rule(data)
+		if !ok {
+			continue
+		}
+
+		if inner == nil {
+			return typ, nil, nil
+		}
+
+		innerTyp, err := JsonTypeOf(inner)
+		if err != nil {
+			return Undefined, nil, err
+		}
+
+		if typ == Undefined {
+			typ = innerTyp
+		}
+
+		return typ, inner, nil
+	}
+
+	typ, err := JsonTypeOf(data)
+	return typ, data, err
+}
+
+// A rule recognizing a document that begins with a UTF-8 byte-order mark, as some editors save files, as the JSON after it
+func BOMPrefixed(data []byte) (JsonType, []byte, bool) {
+	rest, ok := bytes.CutPrefix(data, []byte("\xef\xbb\xbf"))
+	return Undefined, rest, ok
+}
+
+// Returns a rule recognizing a literal outside JSON, such as NaN, Infinity, or undefined, surrounded by optional whitespace, as a value of type typ
+func Literal(lit string, typ JsonType) Heuristic {
+	return func(data []byte) (JsonType, []byte, bool) {
+		return typ, nil, string(bytes.TrimSpace(data)) == lit
+	}
+}
@@ -0,0 +1,159 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// A JSON request or response body seen by DescribeHandler
+type BodyDescription struct {
+	// The request the body was sent with or in answer to
+	Request *http.Request
+	// Whether the body is the response's rather than the request's
+	Response bool
+	// The response status; 0 for request bodies
+	Status int
+	// The body as captured, redacted under WithRedactRules, and cut short after WithMaxBytes; nil if it could not be redacted
+	Body []byte
+	// Describes the body; nil if Err is set
+	Description *JsonDescription
+	// Why the body could not be described, such as invalid JSON or a LimitError
+	Err error
+}
+
+// Wraps next so that every request and response body with a JSON content type is described and handed to fn, for observing an API's payloads
+//
+// A request body is described before next is called, and next reads it unchanged; a response body is described once next returns. Bodies with a Content-Encoding other than identity are passed over. Under WithMaxBytes only that much of a body is kept, so that larger bodies are reported with a LimitError without being held in memory. Honors WithRedactRules, WithMaxBytes, and the options Describe honors.
+func DescribeHandler(next http.Handler, fn func(*BodyDescription), opts ...Option) http.Handler {
+	cfg := newConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && isJsonBody(r.Header) {
+			data, err := captureBody(r.Body, cfg.maxBytes)
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+
+			if err == nil {
+				fn(cfg.describeBody(&BodyDescription{Request: r, Body: data}, opts))
+			}
+		}
+
+		rec := &bodyRecorder{ResponseWriter: w, max: cfg.maxBytes}
+		next.ServeHTTP(rec, r)
+
+		if rec.capturing {
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			fn(cfg.describeBody(&BodyDescription{Request: r, Response: true, Status: rec.status, Body: rec.body.Bytes()}, opts))
+		}
+	})
+}
+
+// Returns a callback for DescribeHandler that logs each body's description, e.g. "POST /orders response 201: an object with 3 members"
+func LogDescriptions(l *log.Logger, opts ...Option) func(*BodyDescription) {
+	return func(b *BodyDescription) {
+		side := "request"
+		if b.Response {
+			side = "response " + strconv.Itoa(b.Status)
+		}
+
+		if b.Err != nil {
+			l.Printf("%s %s %s: %v", b.Request.Method, b.Request.URL.Path, side, b.Err)
+			return
+		}
+
+		l.Printf("%s %s %s: %s", b.Request.Method, b.Request.URL.Path, side, b.Description.Friendly(opts...))
+	}
+}
+
+// Redacts and describes a captured body
+func (c *config) describeBody(b *BodyDescription, opts []Option) *BodyDescription {
+	if len(c.redactRules) > 0 {
+		redacted, err := Redact(b.Body, c.redactRules...)
+		if err != nil {
+			// Report why, as Describe would, without handing on a body that could not be redacted
+			if _, invalid := Describe(b.Body, opts...); invalid != nil {
+				err = invalid
+			}
+			b.Body, b.Err = nil, err
+			return b
+		}
+		b.Body = redacted
+	}
+
+	b.Description, b.Err = Describe(b.Body, opts...)
+	if b.Err != nil {
+		b.Description = nil
+	}
+
+	return b
+}
+
+// Reports whether headers announce an uncompressed JSON body: application/json or any type with a +json suffix
+func isJsonBody(h http.Header) bool {
+	if enc := h.Get("Content-Encoding"); enc != "" && !strings.EqualFold(enc, "identity") {
+		return false
+	}
+
+	typ, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+
+	return err == nil && (typ == "application/json" || strings.HasSuffix(typ, "+json"))
+}
+
+// Reads a body whole, or up to one byte past max when max is set, leaving the rest unread
+func captureBody(r io.Reader, max int) ([]byte, error) {
+	if max > 0 {
+		r = io.LimitReader(r, int64(max)+1)
+	}
+
+	return io.ReadAll(r)
+}
+
+// Passes a response through while keeping a copy of its body if it is JSON
+type bodyRecorder struct {
+	http.ResponseWriter
+	max       int
+	status    int
+	decided   bool
+	capturing bool
+	body      bytes.Buffer
+}
+
+func (b *bodyRecorder) WriteHeader(status int) {
+	if !b.decided {
+		b.decided = true
+		b.status = status
+		b.capturing = isJsonBody(b.Header())
+	}
+
+	b.ResponseWriter.WriteHeader(status)
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	if !b.decided {
+		b.WriteHeader(http.StatusOK)
+	}
+
+	if b.capturing {
+		keep := p
+		if b.max > 0 && b.body.Len()+len(keep) > b.max+1 {
+			keep = keep[:b.max+1-b.body.Len()]
+		}
+		b.body.Write(keep)
+	}
+
+	return b.ResponseWriter.Write(p)
+}
+
+// Lets http.ResponseController reach the underlying writer, for flushing and the like
+func (b *bodyRecorder) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
@@ -0,0 +1,122 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+)
+
+// Iterates over the object's members in sorted key order, since the map keeps no other; see OrderedRawObject and Members for document order
+func (o *RawObject) All() iter.Seq2[string, json.RawMessage] {
+	return func(yield func(string, json.RawMessage) bool) {
+		for _, k := range sortedKeys(*o) {
+			if !yield(k, (*o)[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the object's keys in sorted order
+func (o *RawObject) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, k := range sortedKeys(*o) {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the object's values in the sorted order of their keys
+func (o *RawObject) Values() iter.Seq[json.RawMessage] {
+	return func(yield func(json.RawMessage) bool) {
+		for _, k := range sortedKeys(*o) {
+			if !yield((*o)[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the object's members in document order
+func (o *OrderedRawObject) All() iter.Seq2[string, json.RawMessage] {
+	return func(yield func(string, json.RawMessage) bool) {
+		for _, m := range o.members {
+			if !yield(m.Key, m.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the object's keys in document order
+func (o *OrderedRawObject) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, m := range o.members {
+			if !yield(m.Key) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the object's values in document order
+func (o *OrderedRawObject) Values() iter.Seq[json.RawMessage] {
+	return func(yield func(json.RawMessage) bool) {
+		for _, m := range o.members {
+			if !yield(m.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the array's elements with their indices
+func (a *RawArray) All() iter.Seq2[int, json.RawMessage] {
+	return func(yield func(int, json.RawMessage) bool) {
+		for i, elem := range *a {
+			if !yield(i, elem) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over the array's elements in order
+func (a *RawArray) Values() iter.Seq[json.RawMessage] {
+	return func(yield func(json.RawMessage) bool) {
+		for _, elem := range *a {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over a JSON object's members in document order, which a RawObject cannot keep
+//
+// A repeated key is yielded each time it appears. The object is split up front, so any error is returned before iterating; data that is valid JSON of another type fails with an *ErrUnexpectedType.
+func Members(data []byte) (iter.Seq2[string, json.RawMessage], error) {
+	typ, err := JsonTypeOf(bytes.TrimSpace(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != Object {
+		return nil, &ErrUnexpectedType{Want: Object, Got: typ}
+	}
+
+	members, err := orderedMembers(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(string, json.RawMessage) bool) {
+		for _, m := range members {
+			if !yield(m.Key, m.Value) {
+				return
+			}
+		}
+	}, nil
+}
@@ -2,8 +2,10 @@ package jsondescriber
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -11,6 +13,34 @@ import (
 type JsonDescription struct {
 	Element string
 	Members map[string]uint
+	// Set when Members counts only a sample of a top-level array; see WithSampleSize
+	Sample *Sample
+	// The seed given to WithSeed, recorded whether or not anything was chosen at random, so that the run can be repeated; nil without one
+	Seed *int64
+	// Statistics for the numbers at each path, with array indices as wildcards; only filled in under WithNumericStats
+	Numbers map[string]*NumberStats
+	// Counts of string members by recognized format, e.g. "UUID"; only filled in under WithStringFormats
+	Formats map[string]uint
+	// Counts of members by the name of the detector that recognized them, e.g. "money amount"; only filled in under WithDetectors
+	Detected map[string]uint
+	// The combined members of every member object or array, keyed by "object" and "array"; only filled in under WithNesting
+	Nested map[string]*JsonDescription
+	// Set on a nested description when every container it combines had the same member counts
+	Uniform bool
+	// The keys of a top-level object in the order first seen; only filled in under WithKeyNames
+	Keys []string
+	// Members of a top-level object or array left out because they were malformed, in document order; only filled in under WithLenient
+	Skipped []*MemberError
+}
+
+// Records how the elements counted by a sampled JsonDescription were chosen
+type Sample struct {
+	// How many elements were counted
+	Size uint
+	// Whether the elements were chosen at random from the whole array rather than taken from its start
+	Random bool
+	// The seed given to WithSeed, when Random
+	Seed int64
 }
 
 // Constructor for JsonDescription that initializes its Members counter
@@ -21,6 +51,12 @@ func NewJsonDescription() *JsonDescription {
 	}
 }
 
+// A single key:type pair from an inventory
+type KeyType struct {
+	Key  string
+	Type string
+}
+
 // A container for json.RawMessage from an array
 type RawArray []json.RawMessage
 
@@ -28,30 +64,181 @@ type RawArray []json.RawMessage
 type RawObject map[string]json.RawMessage
 
 // Each JSON element type except number is uniquely identifiable from its first character
-var heuristics = map[string]string{
-	`{`: `object`,
-	`[`: `array`,
-	`"`: `string`,
-	`t`: `true`,
-	`f`: `false`,
-	`n`: `null`,
+var heuristics = map[string]JsonType{
+	`{`: Object,
+	`[`: Array,
+	`"`: String,
+	`t`: True,
+	`f`: False,
+	`n`: Null,
 }
 
-// Inverts a JsonDescription.Members into []"%uint %type(s)" with correct plurals
-func descElem(counts map[string]uint) []string {
+// Inverts a JsonDescription.Members into []"%uint %type(s)" with correct plurals, ordered by WithMemberOrder
+//
+// Recognized string formats are noted after the strings, e.g. "2 strings (1 ISO-8601 date and 1 UUID)", and nested descriptions after their containers, down to depth levels.
+func descElem(counts, formats map[string]uint, nested map[string]*JsonDescription, cfg *config, depth int) []string {
 	var list = make([]string, 0)
 
-	for k := range counts {
-		count := counts[k]
+	for _, tc := range sortCounts(counts, cfg.memberOrder) {
+		count := tc.Count
+		desc := ""
 		if count > 1 {
-			desc := fmt.Sprintf("%d %ss", count, k)
-			list = append(list, desc)
+			desc = fmt.Sprintf("%s %ss", cfg.count(count), tc.Type)
 		} else if count == 1 {
-			desc := fmt.Sprintf("%d %s", count, k)
-			list = append(list, desc)
+			desc = fmt.Sprintf("%s %s", cfg.count(count), tc.Type)
+		} else {
+			continue
+		}
+
+		if tc.Type == "string" && len(formats) > 0 {
+			desc += " (" + cfg.joinList(descFormats(formats, cfg)) + ")"
+		}
+
+		if inner, ok := nested[tc.Type]; ok && depth > 0 {
+			desc = descNested(desc, tc, inner, cfg, depth-1)
+		}
+
+		list = append(list, desc)
+	}
+
+	return list
+}
+
+// Extends desc, e.g. "2 objects", with what the containers it counts hold: "1 array of 4 numbers", "1 object (with 1 number and 1 string)", "2 objects (each with 3 strings)", or "2 objects (with 5 strings in total)"
+func descNested(desc string, tc TypeCount, inner *JsonDescription, cfg *config, depth int) string {
+	prep := "with"
+
+	if tc.Type == "array" {
+		prep = "of"
+	}
+
+	if !hasMembers(inner.Members) {
+		return strings.Replace(desc, " ", " empty ", 1)
+	}
+
+	if tc.Count > 1 && inner.Uniform {
+		inner = inner.share(tc.Count)
+	}
+
+	var (
+		items = descElem(inner.Members, inner.Formats, inner.Nested, cfg, depth)
+		list  = cfg.joinList(items)
+	)
+
+	// A longer list is parenthesized so its "and" cannot be mistaken for the enclosing one
+	switch {
+	case tc.Count == 1 && len(items) == 1:
+		return desc + " " + prep + " " + list
+	case tc.Count == 1:
+		return desc + " (" + prep + " " + list + ")"
+	case inner.Uniform:
+		return desc + " (each " + prep + " " + list + ")"
+	}
+
+	return desc + " (" + prep + " " + list + " in total)"
+}
+
+// Divides a uniform nested description combining k containers into what each one holds
+//
+// Formats and non-uniform nested descriptions are not tracked per container, so they are kept only where they divide evenly.
+func (jd *JsonDescription) share(k uint) *JsonDescription {
+	each := &JsonDescription{Element: jd.Element, Members: make(map[string]uint, len(jd.Members)), Uniform: true}
+
+	for t, n := range jd.Members {
+		each.Members[t] = n / k
+	}
+
+	for f, n := range jd.Formats {
+		if n%k != 0 {
+			each.Formats = nil
+			break
+		}
+		if each.Formats == nil {
+			each.Formats = make(map[string]uint)
+		}
+		each.Formats[f] = n / k
+	}
+
+	for t, inner := range jd.Nested {
+		if !inner.Uniform {
+			continue
+		}
+		if each.Nested == nil {
+			each.Nested = make(map[string]*JsonDescription)
+		}
+		each.Nested[t] = inner.share(k)
+	}
+
+	return each
+}
+
+// Lists keys for display, e.g. "key id" or "keys id, name, and tags"; past the first WithTopN keys the rest are rolled into "and 37 others"
+func descKeys(keys []string, cfg *config) string {
+	var (
+		shown = keys
+		safe  []string
+	)
+
+	if cfg.topN > 0 && len(keys) > cfg.topN {
+		shown = keys[:cfg.topN]
+	}
+
+	for _, k := range shown {
+		safe = append(safe, SafeKey(k))
+	}
+
+	if rest := uint(len(keys) - len(shown)); rest > 0 {
+		safe = append(safe, cfg.others(rest))
+	}
+
+	if len(keys) == 1 {
+		return "key " + safe[0]
+	}
+
+	return "keys " + cfg.joinList(safe)
+}
+
+// Keeps the n most common member types, reporting how many members the rest account for
+func topCounts(counts map[string]uint, n int) (map[string]uint, uint) {
+	var (
+		top  = make(map[string]uint, n)
+		rest uint
+	)
+
+	for i, tc := range sortCounts(counts, ByCount) {
+		if i < n {
+			top[tc.Type] = tc.Count
+		} else {
+			rest += tc.Count
+		}
+	}
+
+	return top, rest
+}
+
+// Reports whether any member type has a nonzero count
+func hasMembers(counts map[string]uint) bool {
+	for _, n := range counts {
+		if n > 0 {
+			return true
 		}
 	}
 
+	return false
+}
+
+// Lists string format or detected type counts such as "2 UUIDs", ordered by WithMemberOrder
+func descFormats(formats map[string]uint, cfg *config) []string {
+	var list = make([]string, 0, len(formats))
+
+	for _, tc := range sortCounts(formats, cfg.memberOrder) {
+		name := tc.Type
+		if tc.Count > 1 {
+			name = pluralFormat(name)
+		}
+		list = append(list, fmt.Sprintf("%s %s", cfg.count(tc.Count), name))
+	}
+
 	return list
 }
 
@@ -71,12 +258,143 @@ func (o *RawObject) Inventory() map[string]string {
 	return inv
 }
 
+// Lists the key:type pairs of a RawObject sorted by key, for stable output
+func (o *RawObject) InventorySorted() []KeyType {
+	return sortInventory(o.Inventory())
+}
+
+// Creates a path:type mapping covering every nested member of a RawObject, so nested schemas can be compared key-for-key
+//
+// Containers are listed along with their contents. Honors WithPathStyle.
+func (o *RawObject) DeepInventory(opts ...Option) map[string]string {
+	var (
+		cfg = newConfig(opts)
+		inv = make(map[string]string)
+		obj = *o
+	)
+
+	for k := range obj {
+		deepInventory(obj[k], joinKey(cfg.pathStyle, "", k), cfg.pathStyle, inv)
+	}
+
+	return inv
+}
+
+// Lists the path:type pairs of DeepInventory sorted by path, for stable output
+//
+// Honors WithPathStyle.
+func (o *RawObject) DeepInventorySorted(opts ...Option) []KeyType {
+	return sortInventory(o.DeepInventory(opts...))
+}
+
+// Flattens an inventory into a slice sorted by key
+func sortInventory(inv map[string]string) []KeyType {
+	list := make([]KeyType, 0, len(inv))
+
+	for _, k := range sortedKeys(inv) {
+		list = append(list, KeyType{Key: k, Type: inv[k]})
+	}
+
+	return list
+}
+
+// Returns the keys of a map in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Records the type of raw at path, then recurses into its members
+func deepInventory(raw json.RawMessage, path string, style PathStyle, inv map[string]string) {
+	typ, _ := TypeOf(raw)
+	inv[path] = *typ
+
+	if *typ == "object" {
+		obj := make(RawObject)
+		json.Unmarshal(raw, &obj)
+
+		for k := range obj {
+			deepInventory(obj[k], joinKey(style, path, k), style, inv)
+		}
+	}
+
+	if *typ == "array" {
+		arr := make(RawArray, 0)
+		json.Unmarshal(raw, &arr)
+
+		for i := range arr {
+			deepInventory(arr[i], joinIndex(style, path, i), style, inv)
+		}
+	}
+}
+
+// this.Diff(that) maps member types whose counts differ between two descriptions into three categories: added (absent from this one), deleted (absent from that one), or modified (a different count), each sorted
+//
+// Only Members are compared; compare Element directly to see whether the described value itself changed type.
+func (jd *JsonDescription) Diff(n *JsonDescription) map[string][]string {
+	var (
+		add = make([]string, 0)
+		del = make([]string, 0)
+		mod = make([]string, 0)
+	)
+
+	for _, t := range sortedKeys(jd.Members) {
+		if jd.Members[t] == 0 {
+			continue
+		}
+
+		switch count := n.Members[t]; {
+		case count == 0:
+			del = append(del, t)
+		case count != jd.Members[t]:
+			mod = append(mod, t)
+		}
+	}
+
+	for _, t := range sortedKeys(n.Members) {
+		if n.Members[t] > 0 && jd.Members[t] == 0 {
+			add = append(add, t)
+		}
+	}
+
+	return map[string][]string{
+		"added":    add,
+		"deleted":  del,
+		"modified": mod,
+	}
+}
+
 // Generates a grammatical English-language list from a JsonDescription
-func (jd *JsonDescription) Friendly() string {
-	var descr string = "undefined"
+//
+// Keys recorded under WithKeyNames are listed when WithKeyNames or Verbose is given. Honors WithTemplate, WithMemberOrder, WithNesting, WithVerbosity, WithKeyNames, WithTopN, WithCountWords, WithConjunction, and WithOxfordComma.
+func (jd *JsonDescription) Friendly(opts ...Option) string {
+	var (
+		cfg          = newConfig(opts)
+		descr string = "undefined"
+		depth        = cfg.friendlyDepth()
+	)
 
 	elem := jd.Element
 
+	if cfg.template != nil {
+		if out, ok := jd.execTemplate(cfg.template, cfg, depth); ok {
+			return out
+		}
+	}
+
+	switch cfg.verbosity {
+	case Terse:
+		return jd.terse()
+	case Verbose:
+		depth = int(^uint(0) >> 1)
+	}
+
 	// Descriptions, not values
 	if elem == "string" || elem == "number" {
 		descr = fmt.Sprintf("a %s", elem)
@@ -89,163 +407,329 @@ func (jd *JsonDescription) Friendly() string {
 
 	// Type of container and inventory of elements; not concerned with keys here
 	if elem == "object" || elem == "array" {
-		inv := descElem(jd.Members)
-		count := len(inv)
+		var (
+			counts = jd.Members
+			rest   uint
+		)
+
+		if cfg.topN > 0 {
+			counts, rest = topCounts(jd.Members, cfg.topN)
+		}
+
+		inv := descElem(counts, jd.Formats, jd.Nested, cfg, depth)
+
+		if rest > 0 {
+			inv = append(inv, cfg.others(rest))
+		}
 
-		// Oxfordize
-		if count == 1 {
+		if len(inv) > 0 && len(jd.Keys) > 0 && (cfg.keyNames || cfg.verbosity == Verbose) {
 			descr = fmt.Sprintf(
-				"an %s with %s",
+				"an %s with %s (%s)",
 				elem,
-				inv[0],
+				descKeys(jd.Keys, cfg),
+				cfg.joinList(inv),
 			)
-		} else if count == 2 {
+		} else if len(inv) > 0 {
 			descr = fmt.Sprintf(
 				"an %s with %s",
 				elem,
-				strings.Join(inv, " and "),
-			)
-		} else if count > 2 {
-			descr = fmt.Sprintf(
-				"an %s with %s, and %s",
-				elem,
-				strings.Join(inv[:count-1], ", "),
-				inv[count-1],
+				cfg.joinList(inv),
 			)
+		}
+
+		if len(inv) > 0 {
+			if jd.Sample != nil && cfg.verbosity == Verbose {
+				descr += " (" + jd.Sample.explain() + ")"
+			} else if jd.Sample != nil {
+				descr += " (sampled)"
+			}
+
+			if len(jd.Detected) > 0 {
+				descr += " (" + cfg.joinList(descFormats(jd.Detected, cfg)) + ")"
+			}
 		} else {
 			descr = fmt.Sprintf(
 				"an empty %s",
 				elem,
 			)
 		}
+
+		if len(jd.Skipped) > 0 {
+			descr += " (" + jd.skipped(cfg) + ")"
+		}
 	}
 
 	return descr
 }
 
 // Generates a populated JsonDescription from a raw JSON []byte
-func Describe(data []byte) (*JsonDescription, error) {
+//
+// Validation and counting happen in a single pass. A key repeated within a top-level object is counted once, by its last value. Honors WithMaxDepth, WithMaxBytes, WithMaxMembers, WithParallelism, WithSampleSize, WithSeed, WithNumericStats, WithExactNumbers, WithStringFormats, WithNesting, WithKeyNames, WithLenient, WithHeuristics, WithDetectors, and WithPathStyle.
+func Describe(data []byte, opts ...Option) (*JsonDescription, error) {
+	return describeContext(context.Background(), data, opts)
+}
+
+// Describes data with a pooled Describer, handing back a copy of the result
+func describeContext(ctx context.Context, data []byte, opts []Option) (*JsonDescription, error) {
 	var (
+		d     = describerPool.Get().(*Describer)
 		descr = NewJsonDescription()
-		jt    *string
-		err   error
 	)
 
-	// Bail if this isn't even JSON
-	if jt, err = TypeOf(data); err != nil {
-		return descr, err
+	defer describerPool.Put(d)
+
+	d.cfg = newConfig(opts)
+	shared, err := d.describe(ctx, data)
+
+	descr.Element = shared.Element
+	for t, n := range shared.Members {
+		descr.Members[t] = n
+	}
+
+	if shared.Sample != nil {
+		sample := *shared.Sample
+		descr.Sample = &sample
 	}
 
-	descr.Element = *jt
+	// The Describer lets go of its Seed, Numbers, Formats, Detected, Nested, Keys, and Skipped on Reset, so they can be handed over as they are
+	descr.Seed = shared.Seed
+	descr.Numbers = shared.Numbers
+	descr.Formats = shared.Formats
+	descr.Detected = shared.Detected
+	descr.Nested = shared.Nested
+	descr.Keys = shared.Keys
+	descr.Skipped = shared.Skipped
+
+	d.Reset()
+	return descr, err
+}
 
-	if descr.Element == "object" {
-		jo := make(map[string]json.RawMessage)
-		json.Unmarshal(data, &jo)
+// Validates raw []byte as JSON and determines which element type it is
+func TypeOf(data []byte) (*string, error) {
+	return typeName(JsonTypeOf(data))
+}
 
-		for k := range jo {
-			et, _ := TypeOf(jo[k])
-			descr.Members[*et] += 1
+// Validates raw []byte as JSON and determines which JsonType it is
+func JsonTypeOf(data []byte) (JsonType, error) {
+	if !json.Valid(data) {
+		if nestingDepth(data) > stdlibMaxDepth {
+			return Undefined, ErrTooDeep
 		}
+		return Undefined, ErrInvalidJson
 	}
 
-	if descr.Element == "array" {
-		ja := make(RawArray, 0)
-		json.Unmarshal(data, &ja)
+	if typ, ok := heuristics[string(data[0])]; ok {
+		return typ, nil
+	}
 
-		for i := range ja {
-			et, _ := TypeOf(ja[i])
-			descr.Members[*et] += 1
-		}
+	return Number, nil
+}
+
+// Adapts a JsonType result to the *string form of TypeOf, which is empty on error
+func typeName(t JsonType, err error) (*string, error) {
+	var typ string
+
+	if t != Undefined {
+		typ = t.String()
 	}
 
-	return descr, err
+	return &typ, err
 }
 
-// Validates raw []byte as JSON and determines which element type it is
-func TypeOf(data []byte) (*string, error) {
+// Reports bytes left over after the first complete JSON value, e.g. a second document concatenated to the first
+type TrailingDataError struct {
+	// Byte offset at which the extra data begins
+	Offset int64
+}
+
+func (e *TrailingDataError) Error() string {
+	return fmt.Sprintf("trailing data after json value at offset %d", e.Offset)
+}
+
+// Makes a TrailingDataError match ErrInvalidJson, since the document as a whole is not valid
+func (e *TrailingDataError) Unwrap() error {
+	return ErrInvalidJson
+}
+
+// Like TypeOf, but when data holds a complete value followed by more than whitespace, returns that value's type and a *TrailingDataError locating the extra data
+func TypeOfStrict(data []byte) (*string, error) {
+	return typeName(JsonTypeOfStrict(data))
+}
+
+// Like JsonTypeOf, but when data holds a complete value followed by more than whitespace, returns that value's type and a *TrailingDataError locating the extra data
+func JsonTypeOfStrict(data []byte) (JsonType, error) {
+	if typ, err := JsonTypeOf(bytes.TrimSpace(data)); err == nil {
+		return typ, nil
+	}
+
 	var (
-		typ string
-		err error
+		dec   = json.NewDecoder(bytes.NewReader(data))
+		first json.RawMessage
 	)
 
-	if !json.Valid(data) {
-		err = fmt.Errorf("not valid json")
-		return &typ, err
+	if err := dec.Decode(&first); err != nil {
+		if nestingDepth(data) > stdlibMaxDepth {
+			return Undefined, ErrTooDeep
+		}
+		return Undefined, ErrInvalidJson
 	}
 
-	if typ = heuristics[string(data[0])]; typ == "" {
-		typ = "number"
+	off := dec.InputOffset()
+
+	for off < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n"), data[off]) >= 0 {
+		off++
 	}
 
-	return &typ, err
+	typ, _ := JsonTypeOf(first)
+	return typ, &TrailingDataError{Offset: off}
 }
 
-// this.Diff(that) maps keys of elements changed from this *RawObject to that one into four categories: added, deleted, modified, or typechanged
-func (o *RawObject) Diff(n *RawObject) map[string][]string {
+// this.Compare(that) sorts keys of elements changed from this *RawObject to that one into a DiffResult: added, deleted, modified, or typechanged
+//
+// A key set to null is present, so it is deleted only once it is missing altogether; DiffResult.Presence tells the two apart. Honors WithIgnoreKeys, WithIgnorePaths, WithComparator, WithUnorderedArrays, WithCaseInsensitiveKeys, WithKeyNormalizer, WithRenameDetection, and WithReplaceThreshold. Size guards need an error to report, so they are enforced only by CompareContext.
+func (o *RawObject) Compare(n *RawObject, opts ...Option) *DiffResult {
+	diff, _ := o.CompareContext(context.Background(), n, append(opts, WithMaxDepth(0), WithMaxBytes(0), WithMaxMembers(0))...)
+	return diff
+}
+
+// Like Compare, but gives up with ctx.Err() once ctx is done, checking between keys
+//
+// Also honors WithMaxDepth, WithMaxBytes, and WithMaxMembers, which apply to each object in turn.
+func (o *RawObject) CompareContext(ctx context.Context, n *RawObject, opts ...Option) (*DiffResult, error) {
 	var (
-		add = make([]string, 0)
-		del = make([]string, 0)
-		mod = make([]string, 0)
-		typ = make([]string, 0)
+		cfg  = newConfig(opts)
+		add  = make([]string, 0)
+		del  = make([]string, 0)
+		mod  = make([]string, 0)
+		typ  = make([]string, 0)
+		pres = make(map[string]KeyPresence)
+		resp = make([]KeyPair, 0)
+		ren  = make([]KeyPair, 0)
+		rep  = make([]string, 0)
 	)
 
 	this := *o
 	that := *n
 
+	for _, obj := range []RawObject{this, that} {
+		if err := cfg.checkObjectLimits(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.matchesKeys() {
+		resp = cfg.respell(this, that)
+		this = this.respelled(resp)
+	}
+
 	for k := range this {
-		if that[k] == nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ptr := joinKey(PointerPath, "", k)
+
+		if cfg.ignores(k, ptr) {
+			continue
+		}
+
+		old, op := this.presence(k)
+		new, np := that.presence(k)
+		changed := true
+
+		if np == PresenceMissing {
 			del = append(del, k)
 		} else {
-			ot, _ := TypeOf(this[k])
-			nt, _ := TypeOf(that[k])
-
-			if *ot != *nt {
-				typ = append(typ, k)
-			} else if !bytes.Equal(this[k], that[k]) {
+			switch cfg.compare(k, old, new) {
+			case VerdictModified:
 				mod = append(mod, k)
+			case VerdictTypeChanged:
+				typ = append(typ, k)
+			case VerdictUnchanged:
+				changed = false
+			default:
+				ot, _ := TypeOf(old)
+				nt, _ := TypeOf(new)
+
+				if *ot != *nt {
+					typ = append(typ, k)
+				} else if po, pn := cfg.prune(old, ptr), cfg.prune(new, ptr); bytes.Equal(po, pn) {
+					changed = false
+				} else if cfg.replaceThreshold > 0 && cfg.valueSimilarity(po, pn) < cfg.replaceThreshold {
+					rep = append(rep, k)
+				} else {
+					mod = append(mod, k)
+				}
 			}
 		}
+
+		if changed {
+			pres[k] = KeyPresence{Old: op, New: np}
+		}
 	}
 
 	for k := range that {
-		if this[k] == nil {
+		if cfg.ignores(k, joinKey(PointerPath, "", k)) {
+			continue
+		}
+
+		if _, ok := this[k]; !ok {
+			_, np := that.presence(k)
 			add = append(add, k)
+			pres[k] = KeyPresence{Old: PresenceMissing, New: np}
 		}
 	}
 
-	return map[string][]string{
-		"added":       add,
-		"deleted":     del,
-		"modified":    mod,
-		"typechanged": typ,
+	sort.Strings(add)
+	sort.Strings(del)
+	sort.Strings(mod)
+	sort.Strings(rep)
+	sort.Strings(typ)
+
+	if cfg.detectRenames {
+		ren, del, add = cfg.renames(this, that, del, add)
 	}
+
+	return &DiffResult{
+		Added:       add,
+		Deleted:     del,
+		Modified:    mod,
+		Replaced:    rep,
+		TypeChanged: typ,
+		Respelled:   resp,
+		Renamed:     ren,
+		Presence:    pres,
+	}, nil
 }
 
-// this.DiffCount(that) counts members changed from this *RawObject to that one: added, deleted, modified, or typechanged
-func (o *RawObject) DiffCount(n *RawObject) map[string]uint {
-	diff := make(map[string]uint)
+// this.Diff(that) maps keys of elements changed from this *RawObject to that one into four categories: added, deleted, modified, or typechanged, each sorted
+//
+// Deprecated: Use Compare, whose DiffResult fields cannot be misspelled.
+func (o *RawObject) Diff(n *RawObject, opts ...Option) map[string][]string {
+	return o.Compare(n, opts...).Map()
+}
 
-	this := *o
-	that := *n
+// Like Diff, but gives up with ctx.Err() once ctx is done, checking between keys
+//
+// Deprecated: Use CompareContext.
+func (o *RawObject) DiffContext(ctx context.Context, n *RawObject, opts ...Option) (map[string][]string, error) {
+	diff, err := o.CompareContext(ctx, n, opts...)
+	if err != nil {
+		return nil, err
+	}
 
-	for k := range this {
-		if that[k] == nil {
-			diff["deleted"] += 1
-		} else {
-			ot, _ := TypeOf(this[k])
-			nt, _ := TypeOf(that[k])
+	return diff.Map(), nil
+}
 
-			if *ot != *nt {
-				diff["typechanged"] += 1
-			} else if !bytes.Equal(this[k], that[k]) {
-				diff["modified"] += 1
-			}
-		}
-	}
+// this.DiffCount(that) counts members changed from this *RawObject to that one: added, deleted, modified, or typechanged
+//
+// Honors the same options as Compare.
+func (o *RawObject) DiffCount(n *RawObject, opts ...Option) map[string]uint {
+	diff := make(map[string]uint)
 
-	for k := range that {
-		if this[k] == nil {
-			diff["added"] += 1
+	for category, keys := range o.Compare(n, opts...).Map() {
+		if len(keys) > 0 {
+			diff[category] = uint(len(keys))
 		}
 	}
 
@@ -267,7 +751,7 @@ func UnmarshalArray(in []byte) (*RawArray, error) {
 	}
 
 	if *typ != "array" {
-		err = fmt.Errorf("given []byte is %s, expected array", *typ)
+		err = &ErrUnexpectedType{Want: Array, Got: ParseJsonType(*typ)}
 		return arr, err
 	}
 
@@ -290,7 +774,7 @@ func UnmarshalObject(in []byte) (*RawObject, error) {
 	}
 
 	if *typ != "object" {
-		err = fmt.Errorf("given []byte is %s, expected object", *typ)
+		err = &ErrUnexpectedType{Want: Object, Got: ParseJsonType(*typ)}
 		return obj, err
 	}
 
@@ -0,0 +1,124 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Describes data the way Describe did before the single-pass scan: json.Valid over the whole input, json.Unmarshal, then TypeOf on every member
+func describeUnmarshal(data []byte) (*JsonDescription, error) {
+	var (
+		descr = NewJsonDescription()
+		jt    *string
+		err   error
+	)
+
+	if jt, err = TypeOf(data); err != nil {
+		return descr, err
+	}
+
+	descr.Element = *jt
+
+	switch descr.Element {
+	case "object":
+		jo := make(map[string]json.RawMessage)
+
+		if err = json.Unmarshal(data, &jo); err != nil {
+			return descr, err
+		}
+
+		for k := range jo {
+			et, err := TypeOf(jo[k])
+			if err != nil {
+				return descr, err
+			}
+			descr.Members[*et]++
+		}
+	case "array":
+		ja := make(RawArray, 0)
+
+		if err = json.Unmarshal(data, &ja); err != nil {
+			return descr, err
+		}
+
+		for i := range ja {
+			et, err := TypeOf(ja[i])
+			if err != nil {
+				return descr, err
+			}
+			descr.Members[*et]++
+		}
+	}
+
+	return descr, nil
+}
+
+// Inputs for BenchmarkDescribe: a small object, and an object and an array of 5000 nested members each, of about 1.2 MB
+func benchmarkInputs() []struct {
+	name string
+	data []byte
+} {
+	var (
+		member = `{"id":%d,"name":"member","tags":["a","b","c"],"score":1.5,"ok":true,"extra":null,"pad":"` + strings.Repeat("x", 160) + `"}`
+		object strings.Builder
+		array  strings.Builder
+	)
+
+	object.WriteByte('{')
+	array.WriteByte('[')
+
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			object.WriteByte(',')
+			array.WriteByte(',')
+		}
+		fmt.Fprintf(&object, `"key%d":`+member, i, i)
+		fmt.Fprintf(&array, member, i)
+	}
+
+	object.WriteByte('}')
+	array.WriteByte(']')
+
+	return []struct {
+		name string
+		data []byte
+	}{
+		{"small", []byte(`{"a":1,"b":"two","c":[3],"d":{"e":null},"f":true}`)},
+		{"large-object", []byte(object.String())},
+		{"large-array", []byte(array.String())},
+	}
+}
+
+// Compares the single-pass scan with the Unmarshal-based path it replaced
+func BenchmarkDescribe(b *testing.B) {
+	for _, in := range benchmarkInputs() {
+		want, err := describeUnmarshal(in.data)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if got, err := Describe(in.data); err != nil || got.Element != want.Element || fmt.Sprint(got.Members) != fmt.Sprint(want.Members) {
+			b.Fatalf("%s: Describe gave %v, %v; the Unmarshal path gave %v", in.name, got, err, want)
+		}
+
+		b.Run(in.name+"/unmarshal", func(b *testing.B) {
+			b.SetBytes(int64(len(in.data)))
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				describeUnmarshal(in.data)
+			}
+		})
+
+		b.Run(in.name+"/scan", func(b *testing.B) {
+			b.SetBytes(int64(len(in.data)))
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				Describe(in.data)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package jsondescriber
+
+// One of the seven kinds of JSON value; the zero value is Undefined
+type JsonType int
+
+const (
+	Undefined JsonType = iota
+	Object
+	Array
+	String
+	Number
+	True
+	False
+	Null
+)
+
+var jsonTypeNames = [...]string{
+	Undefined: "undefined",
+	Object:    "object",
+	Array:     "array",
+	String:    "string",
+	Number:    "number",
+	True:      "true",
+	False:     "false",
+	Null:      "null",
+}
+
+// Returns the lowercase name used throughout descriptions, inventories, and diffs, e.g. "object"
+func (t JsonType) String() string {
+	if t < 0 || int(t) >= len(jsonTypeNames) {
+		return jsonTypeNames[Undefined]
+	}
+
+	return jsonTypeNames[t]
+}
+
+// Reports whether values of this type hold other values
+func (t JsonType) IsContainer() bool {
+	return t == Object || t == Array
+}
+
+// Reports whether this is a type other than object, array, or Undefined
+func (t JsonType) IsScalar() bool {
+	return t >= String && t <= Null
+}
+
+// Looks up a JsonType by the name String returns, e.g. from an Inventory; unknown names are Undefined
+func ParseJsonType(name string) JsonType {
+	for t, n := range jsonTypeNames {
+		if n == name {
+			return JsonType(t)
+		}
+	}
+
+	return Undefined
+}
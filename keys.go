@@ -0,0 +1,224 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Categorizes an object key that is legal JSON but likely to surprise a consumer
+type KeyIssueKind string
+
+const (
+	// The key parses as a JSON number, e.g. a serialized map[int]T
+	KeyNumeric KeyIssueKind = "numeric"
+	// The key is the empty string
+	KeyEmpty KeyIssueKind = "empty"
+	// The key contains control or other non-printing characters
+	KeyControl KeyIssueKind = "control"
+	// The key begins or ends with whitespace
+	KeyWhitespace KeyIssueKind = "whitespace"
+	// The key is longer than the configured maximum
+	KeyLong KeyIssueKind = "long"
+)
+
+// Reports an unusual key found by LintKeys, located by the JSON Pointer of its object
+type KeyIssue struct {
+	Path string
+	Key  string
+	Kind KeyIssueKind
+}
+
+var numericKey = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// Walks every object in a document and reports numeric, empty, non-printing, padded, or overly long keys
+//
+// Honors WithMaxKeyLength.
+func LintKeys(data []byte, opts ...Option) ([]KeyIssue, error) {
+	var (
+		cfg    = newConfig(opts)
+		issues = make([]KeyIssue, 0)
+	)
+
+	if _, err := TypeOf(data); err != nil {
+		return issues, err
+	}
+
+	err := lintKeys(bytes.TrimSpace(data), "", cfg, &issues)
+	return issues, err
+}
+
+func lintKeys(raw json.RawMessage, ptr string, cfg *config, issues *[]KeyIssue) error {
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range members {
+			for _, kind := range keyIssues(m.Key, cfg) {
+				*issues = append(*issues, KeyIssue{Path: ptr, Key: m.Key, Kind: kind})
+			}
+			if err = lintKeys(m.Value, joinKey(PointerPath, ptr, m.Key), cfg, issues); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+
+		for i := range arr {
+			if err := lintKeys(arr[i], joinIndex(PointerPath, ptr, i), cfg, issues); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Lists every issue that applies to a single key
+func keyIssues(key string, cfg *config) []KeyIssueKind {
+	var kinds []KeyIssueKind
+
+	if key == "" {
+		return append(kinds, KeyEmpty)
+	}
+
+	if numericKey.MatchString(key) {
+		kinds = append(kinds, KeyNumeric)
+	}
+
+	if strings.IndexFunc(key, func(r rune) bool { return !unicode.IsPrint(r) && r != ' ' }) >= 0 {
+		kinds = append(kinds, KeyControl)
+	}
+
+	if strings.TrimSpace(key) != key {
+		kinds = append(kinds, KeyWhitespace)
+	}
+
+	if cfg.maxKeyLength > 0 && len(key) > cfg.maxKeyLength {
+		kinds = append(kinds, KeyLong)
+	}
+
+	return kinds
+}
+
+// SafeKey returns a key as it should be displayed to a human: unchanged when it is plain, otherwise quoted and escaped so empty, padded, or non-printing keys stay visible
+func SafeKey(key string) string {
+	if key == "" || strings.TrimSpace(key) != key || strings.ContainsRune(key, '"') {
+		return strconv.Quote(key)
+	}
+
+	for _, r := range key {
+		if !unicode.IsPrint(r) && r != ' ' {
+			return strconv.Quote(key)
+		}
+	}
+
+	return key
+}
+
+// Reports a key that appears more than once in the same object, located by the JSON Pointer of its object
+type DuplicateKey struct {
+	Path string
+	Key  string
+	// Byte offsets of each occurrence's opening quote, in document order
+	Offsets []int64
+}
+
+// Walks every object in a document and reports each key that occurs more than once, which json.Unmarshal would silently resolve to the last value
+//
+// Results are ordered by first occurrence.
+func CheckDuplicateKeys(data []byte) ([]DuplicateKey, error) {
+	var (
+		dups = make([]DuplicateKey, 0)
+		dec  = json.NewDecoder(bytes.NewReader(data))
+	)
+
+	if _, err := TypeOf(bytes.TrimSpace(data)); err != nil {
+		return dups, err
+	}
+
+	dec.UseNumber()
+
+	if err := checkDuplicates(dec, data, "", &dups); err != nil {
+		return dups, err
+	}
+
+	sort.SliceStable(dups, func(i, j int) bool {
+		return dups[i].Offsets[0] < dups[j].Offsets[0]
+	})
+
+	return dups, nil
+}
+
+// Consumes one value from dec, recording duplicated keys in any objects within it
+func checkDuplicates(dec *json.Decoder, data []byte, ptr string, dups *[]DuplicateKey) error {
+	tok, err := dec.Token()
+
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		var (
+			keys    = make([]string, 0)
+			offsets = make(map[string][]int64)
+		)
+
+		for dec.More() {
+			off := dec.InputOffset()
+
+			// The decoder stops just past the previous token, so skip the separator to reach the key's quote
+			for off < int64(len(data)) && data[off] != '"' {
+				off++
+			}
+
+			tok, err = dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key := tok.(string)
+			if _, ok := offsets[key]; !ok {
+				keys = append(keys, key)
+			}
+			offsets[key] = append(offsets[key], off)
+
+			if err = checkDuplicates(dec, data, joinKey(PointerPath, ptr, key), dups); err != nil {
+				return err
+			}
+		}
+
+		for _, k := range keys {
+			if len(offsets[k]) > 1 {
+				*dups = append(*dups, DuplicateKey{Path: ptr, Key: k, Offsets: offsets[k]})
+			}
+		}
+
+		_, err = dec.Token()
+
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err = checkDuplicates(dec, data, joinIndex(PointerPath, ptr, i), dups); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token()
+	}
+
+	return err
+}
@@ -0,0 +1,161 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reports a document that exceeds a guard set by WithMaxDepth, WithMaxBytes, or WithMaxMembers
+//
+// Matches ErrTooDeep for depth and ErrTooLarge otherwise.
+type LimitError struct {
+	// One of "depth", "bytes", or "members"
+	Limit string
+	Max   int
+	// Byte offset at which the limit was exceeded; streaming checks may report the end of the offending token instead
+	Offset int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("json exceeds max %s %d at offset %d", e.Limit, e.Max, e.Offset)
+}
+
+func (e *LimitError) Unwrap() error {
+	if e.Limit == "depth" {
+		return ErrTooDeep
+	}
+
+	return ErrTooLarge
+}
+
+// Reports whether any guard is set
+func (c *config) limited() bool {
+	return c.maxDepth > 0 || c.maxBytes > 0 || c.maxMembers > 0
+}
+
+// Checks data against the guards without parsing it; depth is how deeply data is already nested
+//
+// The scan assumes data is valid JSON, so it is run before the costlier validation to fail fast.
+func (c *config) checkLimits(data []byte, depth int) error {
+	if c.maxBytes > 0 && len(data) > c.maxBytes {
+		return &LimitError{Limit: "bytes", Max: c.maxBytes, Offset: int64(c.maxBytes)}
+	}
+
+	if c.maxDepth <= 0 && c.maxMembers <= 0 {
+		return nil
+	}
+
+	var (
+		counts   = make([]int, 0)
+		pending  bool
+		inString bool
+	)
+
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+
+		if inString {
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t', '\r', '\n', ':':
+			continue
+		}
+
+		// An object key or array element begins here
+		if pending && ch != '}' && ch != ']' {
+			if err := c.countMember(counts, int64(i)); err != nil {
+				return err
+			}
+		}
+
+		pending = false
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{', '[':
+			counts = append(counts, 0)
+			pending = true
+			if c.maxDepth > 0 && depth+len(counts) > c.maxDepth {
+				return &LimitError{Limit: "depth", Max: c.maxDepth, Offset: int64(i)}
+			}
+		case '}', ']':
+			if len(counts) > 0 {
+				counts = counts[:len(counts)-1]
+			}
+		case ',':
+			pending = true
+		}
+	}
+
+	return nil
+}
+
+// Counts one more member of the innermost open container, failing past WithMaxMembers
+func (c *config) countMember(counts []int, offset int64) error {
+	top := len(counts) - 1
+
+	if top < 0 {
+		return nil
+	}
+
+	if counts[top]++; c.maxMembers > 0 && counts[top] > c.maxMembers {
+		return &LimitError{Limit: "members", Max: c.maxMembers, Offset: offset}
+	}
+
+	return nil
+}
+
+// Checks an already-split object and each of its values against the guards
+func (c *config) checkObjectLimits(obj RawObject) error {
+	if !c.limited() {
+		return nil
+	}
+
+	if c.maxMembers > 0 && len(obj) > c.maxMembers {
+		return &LimitError{Limit: "members", Max: c.maxMembers}
+	}
+
+	if c.maxDepth == 0 && c.maxBytes == 0 {
+		return nil
+	}
+
+	// The compact encoding: braces, plus quotes, colon, and comma for every member but the last
+	total := 1
+
+	for k, v := range obj {
+		if total += len(k) + len(v) + 4; c.maxBytes > 0 && total > c.maxBytes {
+			return &LimitError{Limit: "bytes", Max: c.maxBytes, Offset: int64(c.maxBytes)}
+		}
+
+		if err := c.checkLimits(v, 1); err != nil {
+			return fmt.Errorf("member %s: %w", SafeKey(k), err)
+		}
+	}
+
+	return nil
+}
+
+// Fails reads once more than max bytes have passed through
+type limitReader struct {
+	r    io.Reader
+	read int64
+	max  int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.max > 0 && l.read > l.max {
+		return 0, &LimitError{Limit: "bytes", Max: int(l.max), Offset: l.max}
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,283 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Decides what Merge does with a key present in both objects with different values
+type MergeStrategy int
+
+const (
+	// The other object's value replaces this one's
+	MergeOverwrite MergeStrategy = iota
+	// This object's value is kept
+	MergeSkip
+	// Merge fails, naming every conflicting key
+	MergeError
+)
+
+// this.Merge(that, strategy) returns a new RawObject holding the members of both, resolving shared keys by strategy
+func (o *RawObject) Merge(n *RawObject, strategy MergeStrategy) (*RawObject, error) {
+	var (
+		merged    = make(RawObject, len(*o)+len(*n))
+		conflicts = make([]string, 0)
+	)
+
+	for k, v := range *o {
+		merged[k] = v
+	}
+
+	for k, v := range *n {
+		old, ok := merged[k]
+
+		if !ok || bytes.Equal(old, v) {
+			merged[k] = v
+			continue
+		}
+
+		switch strategy {
+		case MergeOverwrite:
+			merged[k] = v
+		case MergeError:
+			conflicts = append(conflicts, k)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return &merged, fmt.Errorf("merge conflict on keys: %s", strings.Join(conflicts, ", "))
+	}
+
+	return &merged, nil
+}
+
+// Diff3(base, mine, theirs) maps keys changed from a common ancestor into four categories: mine, theirs, both (the same change on each side), or conflict
+//
+// Additions and deletions count as changes. Honors WithIgnoreKeys and WithIgnorePaths.
+func Diff3(base, mine, theirs *RawObject, opts ...Option) map[string][]string {
+	var (
+		cfg  = newConfig(opts)
+		seen = make(map[string]bool)
+		diff = map[string][]string{
+			"mine":     make([]string, 0),
+			"theirs":   make([]string, 0),
+			"both":     make([]string, 0),
+			"conflict": make([]string, 0),
+		}
+	)
+
+	for _, obj := range []*RawObject{base, mine, theirs} {
+		for k := range *obj {
+			if seen[k] || cfg.ignores(k, joinKey(PointerPath, "", k)) {
+				continue
+			}
+			seen[k] = true
+
+			if category := diff3Key(cfg, base, mine, theirs, k); category != "" {
+				diff[category] = append(diff[category], k)
+			}
+		}
+	}
+
+	for _, keys := range diff {
+		sort.Strings(keys)
+	}
+
+	return diff
+}
+
+// Merge3(base, mine, theirs) combines the non-conflicting changes of both sides, returning the result and the conflicting keys
+//
+// A conflicting key keeps mine's value (or absence). Honors WithIgnoreKeys and WithIgnorePaths; ignored keys keep mine's value too.
+func Merge3(base, mine, theirs *RawObject, opts ...Option) (*RawObject, []string) {
+	var (
+		merged = make(RawObject)
+		diff   = Diff3(base, mine, theirs, opts...)
+	)
+
+	for k, v := range *mine {
+		merged[k] = v
+	}
+
+	for _, k := range diff["theirs"] {
+		if v, ok := (*theirs)[k]; ok {
+			merged[k] = v
+		} else {
+			delete(merged, k)
+		}
+	}
+
+	return &merged, diff["conflict"]
+}
+
+// Classifies a single key for Diff3, returning "" when neither side changed it
+func diff3Key(cfg *config, base, mine, theirs *RawObject, k string) string {
+	b, inBase := (*base)[k]
+	m, inMine := (*mine)[k]
+	t, inTheirs := (*theirs)[k]
+	ptr := joinKey(PointerPath, "", k)
+
+	same := func(x []byte, xok bool, y []byte, yok bool) bool {
+		return xok == yok && bytes.Equal(cfg.prune(x, ptr), cfg.prune(y, ptr))
+	}
+
+	mineChanged := !same(b, inBase, m, inMine)
+	theirsChanged := !same(b, inBase, t, inTheirs)
+
+	switch {
+	case mineChanged && theirsChanged:
+		if same(m, inMine, t, inTheirs) {
+			return "both"
+		}
+		return "conflict"
+	case mineChanged:
+		return "mine"
+	case theirsChanged:
+		return "theirs"
+	}
+
+	return ""
+}
+
+// Decides how DeepMerge combines two arrays at the same location
+type ArrayMergeStrategy int
+
+const (
+	// The other object's array replaces this one's
+	ArrayReplace ArrayMergeStrategy = iota
+	// The other array's elements are appended to this one's
+	ArrayConcat
+	// Like ArrayConcat, but elements already present are not repeated
+	ArrayUnion
+)
+
+// this.DeepMerge(that) returns a new RawObject in which nested objects are merged recursively
+//
+// Arrays are combined by WithArrayMerge (default ArrayReplace); any other differing values, including type mismatches, are resolved by WithMergeStrategy (default MergeOverwrite). Nested objects keep this object's key order, followed by keys new in that one.
+func (o *RawObject) DeepMerge(n *RawObject, opts ...Option) (*RawObject, error) {
+	var (
+		cfg    = newConfig(opts)
+		merged = make(RawObject, len(*o)+len(*n))
+	)
+
+	for k, v := range *o {
+		merged[k] = v
+	}
+
+	for _, k := range sortedKeys(*n) {
+		old, ok := merged[k]
+
+		if !ok {
+			merged[k] = (*n)[k]
+			continue
+		}
+
+		v, err := cfg.mergeValues(old, (*n)[k], joinKey(PointerPath, "", k))
+
+		if err != nil {
+			return &merged, err
+		}
+
+		merged[k] = v
+	}
+
+	return &merged, nil
+}
+
+// Merges two values found at ptr
+func (c *config) mergeValues(left, right json.RawMessage, ptr string) (json.RawMessage, error) {
+	lt, _ := TypeOf(left)
+	rt, _ := TypeOf(right)
+
+	switch {
+	case *lt == "object" && *rt == "object":
+		return c.mergeObjects(left, right, ptr)
+	case *lt == "array" && *rt == "array" && c.arrayMerge != ArrayReplace:
+		return c.mergeArrays(left, right)
+	case *lt == "array" && *rt == "array", bytes.Equal(left, right):
+		return right, nil
+	}
+
+	switch c.mergeStrategy {
+	case MergeSkip:
+		return left, nil
+	case MergeError:
+		return nil, fmt.Errorf("merge conflict at %s", ptr)
+	}
+
+	return right, nil
+}
+
+// Merges two objects member by member, preserving the left object's key order
+func (c *config) mergeObjects(left, right json.RawMessage, ptr string) (json.RawMessage, error) {
+	lm, err := orderedMembers(left)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rm, err := orderedMembers(right)
+
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(lm))
+
+	for i, m := range lm {
+		index[m.Key] = i
+	}
+
+	for _, m := range rm {
+		i, ok := index[m.Key]
+
+		if !ok {
+			index[m.Key] = len(lm)
+			lm = append(lm, m)
+			continue
+		}
+
+		if lm[i].Value, err = c.mergeValues(lm[i].Value, m.Value, joinKey(PointerPath, ptr, m.Key)); err != nil {
+			return nil, err
+		}
+	}
+
+	return marshalMembers(lm), nil
+}
+
+// Concatenates two arrays, dropping repeated elements under ArrayUnion
+func (c *config) mergeArrays(left, right json.RawMessage) (json.RawMessage, error) {
+	var (
+		la   = make(RawArray, 0)
+		ra   = make(RawArray, 0)
+		seen = make(map[string]bool)
+	)
+
+	if err := json.Unmarshal(left, &la); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(right, &ra); err != nil {
+		return nil, err
+	}
+
+	merged := make(RawArray, 0, len(la)+len(ra))
+
+	for _, v := range append(la, ra...) {
+		if c.arrayMerge == ArrayUnion {
+			var key bytes.Buffer
+			json.Compact(&key, v)
+			if seen[key.String()] {
+				continue
+			}
+			seen[key.String()] = true
+		}
+		merged = append(merged, v)
+	}
+
+	return json.Marshal(merged)
+}
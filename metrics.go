@@ -0,0 +1,83 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Receives what an Aggregator does with each document, for exporting counters from a long-running process
+//
+// The methods are called on the goroutine calling Add, and may be called concurrently by Aggregators sharing the same Metrics, so implementations must be safe for concurrent use.
+type Metrics interface {
+	// Called for each document merged, with its size in bytes
+	DocumentAdded(size int)
+	// Called for each document rejected, with the error Add returns
+	DocumentRejected(err error)
+	// Called for each value merged, at any depth, with its type
+	ValueSeen(typ JsonType)
+}
+
+// A Metrics that counts documents, errors, bytes, and values by type, updated atomically so they can be read while documents are being added
+//
+// Counters implements expvar.Var, so expvar.Publish("shapes", counters) serves them at /debug/vars. The zero value is ready to use.
+type Counters struct {
+	documents atomic.Uint64
+	errors    atomic.Uint64
+	bytes     atomic.Uint64
+	types     [len(jsonTypeNames)]atomic.Uint64
+}
+
+func (c *Counters) DocumentAdded(size int) {
+	c.documents.Add(1)
+	c.bytes.Add(uint64(size))
+}
+
+func (c *Counters) DocumentRejected(err error) {
+	c.errors.Add(1)
+}
+
+func (c *Counters) ValueSeen(typ JsonType) {
+	if typ >= 0 && int(typ) < len(c.types) {
+		c.types[typ].Add(1)
+	}
+}
+
+// How many documents have been merged
+func (c *Counters) Documents() uint64 {
+	return c.documents.Load()
+}
+
+// How many documents have been rejected
+func (c *Counters) Errors() uint64 {
+	return c.errors.Load()
+}
+
+// How many bytes the documents merged held
+func (c *Counters) Bytes() uint64 {
+	return c.bytes.Load()
+}
+
+// How many values of each type have been merged, keyed by type name; types not seen are left out
+func (c *Counters) Types() map[string]uint64 {
+	types := make(map[string]uint64)
+
+	for t := range c.types {
+		if n := c.types[t].Load(); n > 0 {
+			types[JsonType(t).String()] = n
+		}
+	}
+
+	return types
+}
+
+// Implements expvar.Var, writing the counters as a JSON object
+func (c *Counters) String() string {
+	out, _ := json.Marshal(struct {
+		Documents uint64            `json:"documents"`
+		Errors    uint64            `json:"errors"`
+		Bytes     uint64            `json:"bytes"`
+		Types     map[string]uint64 `json:"types"`
+	}{c.Documents(), c.Errors(), c.Bytes(), c.Types()})
+
+	return string(out)
+}
@@ -0,0 +1,484 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Reads a single MessagePack value from a byte buffer and writes its JSON equivalent
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+	out  bytes.Buffer
+}
+
+// Transcodes one complete MessagePack value into raw JSON
+func MsgpackToJson(data []byte) ([]byte, error) {
+	dec := &msgpackDecoder{data: data}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty msgpack input")
+	}
+
+	if err := dec.value(); err != nil {
+		return nil, err
+	}
+
+	if dec.pos != len(data) {
+		return nil, fmt.Errorf("trailing data after msgpack value at offset %d", dec.pos)
+	}
+
+	return dec.out.Bytes(), nil
+}
+
+// Transcodes a stream of concatenated MessagePack values into one raw JSON value each
+func MsgpackSequenceToJson(data []byte) ([]json.RawMessage, error) {
+	var (
+		dec  = &msgpackDecoder{data: data}
+		vals = make([]json.RawMessage, 0)
+	)
+
+	for dec.pos < len(data) {
+		if err := dec.value(); err != nil {
+			return vals, err
+		}
+
+		vals = append(vals, append(json.RawMessage(nil), dec.out.Bytes()...))
+		dec.out.Reset()
+	}
+
+	return vals, nil
+}
+
+// Generates a populated JsonDescription from raw MessagePack
+func DescribeMsgpack(data []byte) (*JsonDescription, error) {
+	js, err := MsgpackToJson(data)
+
+	if err != nil {
+		return NewJsonDescription(), err
+	}
+
+	return Describe(js)
+}
+
+// UnmarshalMsgpackObject transcodes a MessagePack map to a new RawObject
+func UnmarshalMsgpackObject(in []byte) (*RawObject, error) {
+	js, err := MsgpackToJson(in)
+
+	if err != nil {
+		return new(RawObject), err
+	}
+
+	return UnmarshalObject(js)
+}
+
+// UnmarshalMsgpackArray transcodes a MessagePack array to a new RawArray
+func UnmarshalMsgpackArray(in []byte) (*RawArray, error) {
+	js, err := MsgpackToJson(in)
+
+	if err != nil {
+		return new(RawArray), err
+	}
+
+	return UnmarshalArray(js)
+}
+
+// Consumes n bytes, failing if the input is too short
+func (d *msgpackDecoder) take(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, fmt.Errorf("unexpected end of msgpack input at offset %d", d.pos)
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// Reads a big-endian unsigned length of the given width in bytes
+func (d *msgpackDecoder) length(width int) (int, error) {
+	b, err := d.take(width)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch width {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	default:
+		return int(binary.BigEndian.Uint32(b)), nil
+	}
+}
+
+// Transcodes the value at the current position
+func (d *msgpackDecoder) value() error {
+	b, err := d.take(1)
+
+	if err != nil {
+		return err
+	}
+
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		d.out.WriteString(strconv.Itoa(int(c)))
+		return nil
+	case c >= 0xe0:
+		d.out.WriteString(strconv.Itoa(int(int8(c))))
+		return nil
+	case c&0xf0 == 0x80:
+		return d.object(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		d.out.WriteString("null")
+	case 0xc2:
+		d.out.WriteString("false")
+	case 0xc3:
+		d.out.WriteString("true")
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1 << (c - 0xc4))
+		if err != nil {
+			return err
+		}
+		return d.bin(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(1 << (c - 0xc7))
+		if err != nil {
+			return err
+		}
+		return d.ext(n)
+	case 0xca:
+		raw, err := d.take(4)
+		if err != nil {
+			return err
+		}
+		return d.float(float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), 32)
+	case 0xcb:
+		raw, err := d.take(8)
+		if err != nil {
+			return err
+		}
+		return d.float(math.Float64frombits(binary.BigEndian.Uint64(raw)), 64)
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		raw, err := d.take(1 << (c - 0xcc))
+		if err != nil {
+			return err
+		}
+		d.out.WriteString(strconv.FormatUint(beUint(raw), 10))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		raw, err := d.take(1 << (c - 0xd0))
+		if err != nil {
+			return err
+		}
+		d.out.WriteString(strconv.FormatInt(beInt(raw), 10))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(1 << (c - 0xd9))
+		if err != nil {
+			return err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.length(2 << (c - 0xdc))
+		if err != nil {
+			return err
+		}
+		return d.array(n)
+	case 0xde, 0xdf:
+		n, err := d.length(2 << (c - 0xde))
+		if err != nil {
+			return err
+		}
+		return d.object(n)
+	default:
+		return fmt.Errorf("invalid msgpack type byte 0x%02x at offset %d", c, d.pos-1)
+	}
+
+	return nil
+}
+
+// Writes a msgpack string as a JSON string
+func (d *msgpackDecoder) str(n int) error {
+	raw, err := d.take(n)
+
+	if err != nil {
+		return err
+	}
+
+	enc, _ := json.Marshal(string(raw))
+	d.out.Write(enc)
+	return nil
+}
+
+// Writes msgpack binary data as a base64 JSON string, matching encoding/json's treatment of []byte
+func (d *msgpackDecoder) bin(n int) error {
+	raw, err := d.take(n)
+
+	if err != nil {
+		return err
+	}
+
+	d.out.WriteByte('"')
+	d.out.WriteString(base64.StdEncoding.EncodeToString(raw))
+	d.out.WriteByte('"')
+	return nil
+}
+
+// Writes a msgpack float; JSON has no representation for NaN or infinities
+func (d *msgpackDecoder) float(f float64, bits int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("msgpack float %v at offset %d has no json equivalent", f, d.pos)
+	}
+
+	d.out.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	return nil
+}
+
+// Writes the timestamp extension as an RFC 3339 string; other extension types are rejected
+func (d *msgpackDecoder) ext(n int) error {
+	tb, err := d.take(1)
+
+	if err != nil {
+		return err
+	}
+
+	raw, err := d.take(n)
+
+	if err != nil {
+		return err
+	}
+
+	if int8(tb[0]) != -1 {
+		return fmt.Errorf("unsupported msgpack extension type %d", int8(tb[0]))
+	}
+
+	var ts time.Time
+
+	switch n {
+	case 4:
+		ts = time.Unix(int64(binary.BigEndian.Uint32(raw)), 0)
+	case 8:
+		v := binary.BigEndian.Uint64(raw)
+		ts = time.Unix(int64(v&0x3ffffffff), int64(v>>34))
+	case 12:
+		ts = time.Unix(int64(binary.BigEndian.Uint64(raw[4:])), int64(binary.BigEndian.Uint32(raw[:4])))
+	default:
+		return fmt.Errorf("invalid msgpack timestamp length %d", n)
+	}
+
+	d.out.WriteString(strconv.Quote(ts.UTC().Format(time.RFC3339Nano)))
+	return nil
+}
+
+// Writes n consecutive msgpack values as a JSON array
+func (d *msgpackDecoder) array(n int) error {
+	d.out.WriteByte('[')
+
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+		if err := d.value(); err != nil {
+			return err
+		}
+	}
+
+	d.out.WriteByte(']')
+	return nil
+}
+
+// Writes n msgpack key/value pairs as a JSON object; integer keys become their decimal strings
+func (d *msgpackDecoder) object(n int) error {
+	d.out.WriteByte('{')
+
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			d.out.WriteByte(',')
+		}
+
+		start := d.out.Len()
+		if err := d.value(); err != nil {
+			return err
+		}
+
+		key := d.out.Bytes()[start:]
+		if key[0] != '"' {
+			if typ, _ := TypeOf(key); *typ != "number" {
+				return fmt.Errorf("unsupported msgpack map key type %s", *typ)
+			}
+			quoted := strconv.Quote(string(key))
+			d.out.Truncate(start)
+			d.out.WriteString(quoted)
+		}
+
+		d.out.WriteByte(':')
+		if err := d.value(); err != nil {
+			return err
+		}
+	}
+
+	d.out.WriteByte('}')
+	return nil
+}
+
+// Decodes a big-endian unsigned integer of up to 8 bytes
+func beUint(b []byte) uint64 {
+	var v uint64
+
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return v
+}
+
+// Decodes a big-endian two's complement integer of up to 8 bytes
+func beInt(b []byte) int64 {
+	v := beUint(b)
+	shift := 64 - 8*uint(len(b))
+	return int64(v<<shift) >> shift
+}
+
+// Encodes raw JSON as a single MessagePack value, using the smallest integer width that fits
+func JsonToMsgpack(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	err := encodeMsgpack(&out, bytes.TrimSpace(data))
+	return out.Bytes(), err
+}
+
+func encodeMsgpack(out *bytes.Buffer, raw json.RawMessage) error {
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+		msgpackHeader(out, len(members), 0x80, 0xde)
+		for _, m := range members {
+			msgpackString(out, m.Key)
+			if err = encodeMsgpack(out, m.Value); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+		msgpackHeader(out, len(arr), 0x90, 0xdc)
+		for i := range arr {
+			if err := encodeMsgpack(out, arr[i]); err != nil {
+				return err
+			}
+		}
+	case "string":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		msgpackString(out, s)
+	case "number":
+		return msgpackNumber(out, string(raw))
+	case "true":
+		out.WriteByte(0xc3)
+	case "false":
+		out.WriteByte(0xc2)
+	case "null":
+		out.WriteByte(0xc0)
+	}
+
+	return nil
+}
+
+// Writes a map or array header; fix is the fixmap/fixarray prefix and wide the 16-bit type byte
+func msgpackHeader(out *bytes.Buffer, n int, fix, wide byte) {
+	switch {
+	case n < 16:
+		out.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		out.WriteByte(wide)
+		out.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		out.WriteByte(wide + 1)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func msgpackString(out *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		out.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		out.WriteByte(0xd9)
+		out.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		out.WriteByte(0xda)
+		out.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		out.WriteByte(0xdb)
+		out.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+
+	out.WriteString(s)
+}
+
+// Writes a JSON number as a msgpack integer when it is one, otherwise as a float64
+func msgpackNumber(out *bytes.Buffer, num string) error {
+	if i, err := strconv.ParseInt(num, 10, 64); err == nil {
+		switch {
+		case i >= 0 && i <= 0x7f, i < 0 && i >= -32:
+			out.WriteByte(byte(i))
+		case i >= math.MinInt8 && i <= math.MaxInt8:
+			out.Write([]byte{0xd0, byte(i)})
+		case i >= math.MinInt16 && i <= math.MaxInt16:
+			out.WriteByte(0xd1)
+			out.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+		case i >= math.MinInt32 && i <= math.MaxInt32:
+			out.WriteByte(0xd2)
+			out.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		default:
+			out.WriteByte(0xd3)
+			out.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		}
+		return nil
+	}
+
+	if u, err := strconv.ParseUint(num, 10, 64); err == nil {
+		out.WriteByte(0xcf)
+		out.Write(binary.BigEndian.AppendUint64(nil, u))
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+
+	if err != nil {
+		return fmt.Errorf("number %s cannot be represented in msgpack", num)
+	}
+
+	out.WriteByte(0xcb)
+	out.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Like Get, but panics if there is no value at pointer; for tests and scripts where the document is known
+func (o *RawObject) MustGet(pointer string) json.RawMessage {
+	val, err := o.Get(pointer)
+	if err != nil {
+		panic(fmt.Errorf("jsondescriber: MustGet(%q): %w", pointer, err))
+	}
+
+	return val
+}
+
+// Like UnmarshalObject, but panics if in is not a JSON object; for tests and scripts where the document is known
+func MustUnmarshalObject(in []byte) *RawObject {
+	obj, err := UnmarshalObject(in)
+	if err != nil {
+		panic(fmt.Errorf("jsondescriber: MustUnmarshalObject: %w", err))
+	}
+
+	return obj
+}
+
+// Like UnmarshalArray, but panics if in is not a JSON array; for tests and scripts where the document is known
+func MustUnmarshalArray(in []byte) *RawArray {
+	arr, err := UnmarshalArray(in)
+	if err != nil {
+		panic(fmt.Errorf("jsondescriber: MustUnmarshalArray: %w", err))
+	}
+
+	return arr
+}
+
+// Like Describe, but panics if data cannot be described; for tests and scripts where the document is known
+//
+// Honors the options Describe honors.
+func MustDescribe(data []byte, opts ...Option) *JsonDescription {
+	jd, err := Describe(data, opts...)
+	if err != nil {
+		panic(fmt.Errorf("jsondescriber: MustDescribe: %w", err))
+	}
+
+	return jd
+}
@@ -0,0 +1,519 @@
+package jsondescriber
+
+import (
+	"math/rand"
+	"net/http"
+	"text/template"
+)
+
+// Configures optional behavior; each function documents which options it honors
+type Option func(*config)
+
+// Settings collected from a list of Options
+type config struct {
+	maxKeyLength      int
+	pathStyle         PathStyle
+	template          *template.Template
+	memberOrder       func(a, b TypeCount) bool
+	ignoreKeys        []string
+	ignorePaths       []string
+	comparators       []Comparator
+	foldKeys          bool
+	keyNormalizer     func(string) string
+	detectRenames     bool
+	replaceThreshold  float64
+	detectMoves       bool
+	mergeStrategy     MergeStrategy
+	arrayMerge        ArrayMergeStrategy
+	unorderedArrays   bool
+	arrayIdentity     string
+	createParents     bool
+	maxDepth          int
+	maxBytes          int
+	maxMembers        int
+	parallel          bool
+	parallelism       int
+	sampleSize        int
+	seeded            bool
+	seed              int64
+	numericStats      bool
+	exactNumbers      bool
+	lenient           bool
+	heuristics        *Heuristics
+	detectors         []Detector
+	stringFormats     bool
+	nesting           int
+	verbosity         Verbosity
+	keyNames          bool
+	topN              int
+	countWords        bool
+	conjunction       string
+	oxfordComma       bool
+	truncate          int
+	color             bool
+	colorSet          bool
+	diffLabels        [2]string
+	contextLines      int
+	width             int
+	fingerprintValues bool
+	clusterThreshold  float64
+	packageName       string
+	dialect           SQLDialect
+	delimiter         rune
+	typeInference     bool
+	columns           []string
+	typeAnnotations   bool
+	redactRules       []RedactRule
+	endpointKeyFn     func(*http.Request) string
+	onShapeChange     func(*ShapeChange)
+	metrics           Metrics
+	decompression     bool
+}
+
+// Applies opts over the package defaults
+func newConfig(opts []Option) *config {
+	c := &config{
+		maxKeyLength:     256,
+		memberOrder:      ByCount,
+		verbosity:        Normal,
+		conjunction:      "and",
+		oxfordComma:      true,
+		diffLabels:       [2]string{"old", "new"},
+		contextLines:     3,
+		width:            120,
+		clusterThreshold: 0.8,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithMaxKeyLength sets the length in bytes above which LintKeys reports a key as long
+func WithMaxKeyLength(n int) Option {
+	return func(c *config) {
+		c.maxKeyLength = n
+	}
+}
+
+// WithPathStyle selects how DeepInventory and other path-keyed results write nested locations
+func WithPathStyle(style PathStyle) Option {
+	return func(c *config) {
+		c.pathStyle = style
+	}
+}
+
+// WithTemplate renders Friendly output through tmpl, executed with a FriendlyData
+//
+// A sub-template named for an element type ({{define "object"}}...{{end}}) takes precedence for that type. If execution fails, Friendly falls back to its default phrasing.
+func WithTemplate(tmpl *template.Template) Option {
+	return func(c *config) {
+		c.template = tmpl
+	}
+}
+
+// WithMemberOrder sets the order in which Friendly lists member types, e.g. ByCount or ByTypeName
+func WithMemberOrder(less func(a, b TypeCount) bool) Option {
+	return func(c *config) {
+		c.memberOrder = less
+	}
+}
+
+// WithVerbosity sets how much Friendly says: Terse, Normal (the default), or Verbose
+func WithVerbosity(v Verbosity) Option {
+	return func(c *config) {
+		c.verbosity = v
+	}
+}
+
+// WithKeyNames makes Describe record the keys of a top-level object in JsonDescription.Keys, and Friendly name them, e.g. "an object with keys id, name, and tags (2 strings and 1 array)"
+func WithKeyNames() Option {
+	return func(c *config) {
+		c.keyNames = true
+	}
+}
+
+// WithTopN makes Friendly name only the n most common member types and the first n keys of a wide object, rolling the rest into "and 37 others"
+//
+// Templates given to WithTemplate still receive every member and key.
+func WithTopN(n int) Option {
+	return func(c *config) {
+		c.topN = n
+	}
+}
+
+// WithCountWords makes Friendly spell out counts below ten, e.g. "two strings and one number"
+func WithCountWords() Option {
+	return func(c *config) {
+		c.countWords = true
+	}
+}
+
+// WithConjunction sets the word Friendly puts before the last item of a list, e.g. "&" or "plus", in place of "and"
+func WithConjunction(word string) Option {
+	return func(c *config) {
+		c.conjunction = word
+	}
+}
+
+// WithOxfordComma sets whether Friendly puts a comma before the conjunction in lists of three or more; it does by default
+func WithOxfordComma(enabled bool) Option {
+	return func(c *config) {
+		c.oxfordComma = enabled
+	}
+}
+
+// WithIgnoreKeys makes Compare and DiffCount disregard members whose key matches any of the globs, at any depth
+func WithIgnoreKeys(globs ...string) Option {
+	return func(c *config) {
+		c.ignoreKeys = append(c.ignoreKeys, globs...)
+	}
+}
+
+// WithIgnorePaths makes Compare and DiffCount disregard values whose JSON Pointer matches any of the globs, e.g. "/meta/request_id" or "/items/*/updated_at"
+func WithIgnorePaths(globs ...string) Option {
+	return func(c *config) {
+		c.ignorePaths = append(c.ignorePaths, globs...)
+	}
+}
+
+// WithComparator registers a Comparator that Compare and DiffCount consult before their built-in comparison; comparators run in the order given
+func WithComparator(cmp Comparator) Option {
+	return func(c *config) {
+		c.comparators = append(c.comparators, cmp)
+	}
+}
+
+// WithCaseInsensitiveKeys makes Compare pair a deleted key with an added one spelled the same but for case, e.g. "userId" and "UserID", reporting it in DiffResult.Respelled and comparing their values as one key's
+func WithCaseInsensitiveKeys() Option {
+	return func(c *config) {
+		c.foldKeys = true
+	}
+}
+
+// WithKeyNormalizer makes Compare pair a deleted key with an added one that fn maps to the same string, as WithCaseInsensitiveKeys pairs keys differing in case; fn may, for instance, apply Unicode normalization or strip underscores
+func WithKeyNormalizer(fn func(key string) string) Option {
+	return func(c *config) {
+		c.keyNormalizer = fn
+	}
+}
+
+// WithRenameDetection makes Compare pair a deleted key with an added one holding an equal value, reporting it in DiffResult.Renamed rather than as a deletion and an addition
+func WithRenameDetection() Option {
+	return func(c *config) {
+		c.detectRenames = true
+	}
+}
+
+// WithReplaceThreshold makes Compare and DiffDetailed report a modified key as replaced when its old and new values are less than t alike, from 0 (nothing shared) to 1 (identical), separating wholesale replacements from tweaks
+//
+// Strings and other scalars are scored by edit distance, containers by the share of their leaves left unchanged.
+func WithReplaceThreshold(t float64) Option {
+	return func(c *config) {
+		c.replaceThreshold = t
+	}
+}
+
+// WithMergeStrategy sets how DeepMerge resolves differing scalars and type mismatches
+func WithMergeStrategy(strategy MergeStrategy) Option {
+	return func(c *config) {
+		c.mergeStrategy = strategy
+	}
+}
+
+// WithArrayMerge sets how DeepMerge combines two arrays at the same location
+func WithArrayMerge(strategy ArrayMergeStrategy) Option {
+	return func(c *config) {
+		c.arrayMerge = strategy
+	}
+}
+
+// WithUnorderedArrays compares arrays as multisets: RawObject.Compare no longer reports reordered arrays as modified, and RawArray.Diff matches elements regardless of position
+func WithUnorderedArrays() Option {
+	return func(c *config) {
+		c.unorderedArrays = true
+	}
+}
+
+// WithMoveDetection makes RawArray.Diff report an element deleted in one place and inserted unchanged in another as moved, with its old and new indices, rather than as a deletion and an insertion; it has no effect under WithUnorderedArrays, where positions do not count
+func WithMoveDetection() Option {
+	return func(c *config) {
+		c.detectMoves = true
+	}
+}
+
+// WithArrayIdentity makes unordered array diffs match object elements by the value of key, so an element whose other members changed is reported as modified; implies WithUnorderedArrays
+func WithArrayIdentity(key string) Option {
+	return func(c *config) {
+		c.unorderedArrays = true
+		c.arrayIdentity = key
+	}
+}
+
+// WithCreateParents makes RawObject.Set create missing intermediate objects along the pointer
+func WithCreateParents() Option {
+	return func(c *config) {
+		c.createParents = true
+	}
+}
+
+// WithTruncate limits each value recorded by DiffDetailed, and each cell Table prints, to n bytes, cut at a character boundary and marked with an ellipsis
+func WithTruncate(n int) Option {
+	return func(c *config) {
+		c.truncate = n
+	}
+}
+
+// WithColor forces Render to color its output, or not, instead of coloring only on a terminal
+func WithColor(enabled bool) Option {
+	return func(c *config) {
+		c.color = enabled
+		c.colorSet = true
+	}
+}
+
+// WithDiffLabels names the two documents in the header of a UnifiedDiff, "old" and "new" by default
+func WithDiffLabels(old, new string) Option {
+	return func(c *config) {
+		c.diffLabels = [2]string{old, new}
+	}
+}
+
+// WithContextLines sets how many unchanged lines UnifiedDiff shows around each change, 3 by default
+func WithContextLines(n int) Option {
+	return func(c *config) {
+		c.contextLines = n
+	}
+}
+
+// WithWidth sets how many characters wide SideBySide output may be, 120 by default
+func WithWidth(n int) Option {
+	return func(c *config) {
+		c.width = n
+	}
+}
+
+// WithFingerprintValues makes Fingerprint hash values as well as structure, so only equal documents share a fingerprint
+func WithFingerprintValues() Option {
+	return func(c *config) {
+		c.fingerprintValues = true
+	}
+}
+
+// WithClusterThreshold sets how Similar, from 0 to 1, a document must be to a family's representative for Cluster to group them; 0.8 by default
+func WithClusterThreshold(t float64) Option {
+	return func(c *config) {
+		c.clusterThreshold = t
+	}
+}
+
+// WithPackageName sets the package declared by the files GoTypes and ProtoMessages generate; GoTypes declares main by default, ProtoMessages no package
+func WithPackageName(name string) Option {
+	return func(c *config) {
+		c.packageName = name
+	}
+}
+
+// WithDialect selects the SQL flavor CreateTable writes; Postgres by default
+func WithDialect(d SQLDialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
+
+// WithDelimiter sets the field separator for reading and writing CSV; a comma by default
+func WithDelimiter(r rune) Option {
+	return func(c *config) {
+		c.delimiter = r
+	}
+}
+
+// WithTypeInference makes ReadCSV read columns of numbers, booleans, or nested JSON as such rather than as strings
+func WithTypeInference() Option {
+	return func(c *config) {
+		c.typeInference = true
+	}
+}
+
+// WithColumns makes Table print only these columns, in this order, rather than every key
+func WithColumns(keys ...string) Option {
+	return func(c *config) {
+		c.columns = keys
+	}
+}
+
+// WithTypeAnnotations makes Table head each column with the types of its values as well as its key, e.g. "id (number)"
+func WithTypeAnnotations() Option {
+	return func(c *config) {
+		c.typeAnnotations = true
+	}
+}
+
+// WithRedactRules makes DescribeHandler redact bodies by these rules, as Redact does, before describing them and handing them on
+func WithRedactRules(rules ...RedactRule) Option {
+	return func(c *config) {
+		c.redactRules = rules
+	}
+}
+
+// WithEndpointKey makes a ProfilingTransport group responses under the name fn gives each request, rather than by method, host, and templated path
+func WithEndpointKey(fn func(*http.Request) string) Option {
+	return func(c *config) {
+		c.endpointKeyFn = fn
+	}
+}
+
+// WithShapeChange makes a ProfilingTransport call fn whenever a response has paths its endpoint's earlier responses did not, or lacks paths they all had
+//
+// fn is called on the goroutine reading the response body, and may be called concurrently for different responses.
+func WithShapeChange(fn func(*ShapeChange)) Option {
+	return func(c *config) {
+		c.onShapeChange = fn
+	}
+}
+
+// WithMetrics makes an Aggregator, including those a ProfilingTransport keeps for each endpoint, report each document it merges or rejects, and each value it merges, to m
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// WithDecompression makes DescribeReader, Aggregator.AddReader, and ReadCSV recognize gzip and zlib input, as from captured HTTP bodies and log archives, and decompress it first
+//
+// WithMaxBytes then limits the decompressed size.
+func WithDecompression() Option {
+	return func(c *config) {
+		c.decompression = true
+	}
+}
+
+// WithMaxDepth makes Describe and CompareContext fail with a LimitError when objects and arrays nest deeper than n
+func WithMaxDepth(n int) Option {
+	return func(c *config) {
+		c.maxDepth = n
+	}
+}
+
+// WithMaxBytes makes Describe and CompareContext fail with a LimitError on input longer than n bytes
+func WithMaxBytes(n int) Option {
+	return func(c *config) {
+		c.maxBytes = n
+	}
+}
+
+// WithMaxMembers makes Describe and CompareContext fail with a LimitError when any object or array holds more than n members
+func WithMaxMembers(n int) Option {
+	return func(c *config) {
+		c.maxMembers = n
+	}
+}
+
+// WithParallelism makes Describe type the elements of a large top-level array on up to n goroutines; n <= 0 means GOMAXPROCS
+func WithParallelism(n int) Option {
+	return func(c *config) {
+		c.parallel = true
+		c.parallelism = n
+	}
+}
+
+// WithSampleSize makes Describe count only n elements of a longer top-level array, marking the description as sampled
+//
+// By default the first n elements are counted and the rest of the array is not read, or validated. With WithSeed, n elements are chosen at random from the whole array instead.
+func WithSampleSize(n int) Option {
+	return func(c *config) {
+		c.sampleSize = n
+	}
+}
+
+// WithSeed makes every randomized choice reproducible from seed, for debugging and CI, and records seed in JsonDescription.Seed, which MarshalJSON writes out
+//
+// Randomness is opt-in: without a seed nothing is chosen at random. With one, WithSampleSize chooses its sample at random from the whole array, the same sample for the same seed and document.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seeded = true
+		c.seed = seed
+	}
+}
+
+// WithSampleSeed makes WithSampleSize choose its sample at random, reproducibly from seed; it is WithSeed under the name it had when sampling was the only randomized choice
+func WithSampleSeed(seed int64) Option {
+	return WithSeed(seed)
+}
+
+// A source of random numbers from the seed given to WithSeed, for a randomized choice to draw on; each call starts the sequence afresh, so a document is treated alike whenever it is described
+func (c *config) rand() *rand.Rand {
+	return rand.New(rand.NewSource(c.seed))
+}
+
+// WithNumericStats makes Describe summarize the numbers at every path in JsonDescription.Numbers: count, range, mean, and how many are integers
+//
+// Indices are replaced by wildcards, so "/readings/*/temp" covers that member of every element.
+func WithNumericStats() Option {
+	return func(c *config) {
+		c.numericStats = true
+	}
+}
+
+// WithExactNumbers keeps numbers as the literals they were written as rather than rounding them through float64, so 64-bit IDs and long decimals survive intact
+//
+// Equal, unordered array matching, and value fingerprints compare numbers by exact decimal value, so 9007199254740993 no longer equals 9007199254740992 while 1, 1.0, and 1e0 still match. WithNumericStats orders numbers exactly and keeps the smallest and largest as written in NumberStats.MinText and MaxText. ToAny decodes numbers as json.Number. Diff and Compare already compare numbers as written.
+func WithExactNumbers() Option {
+	return func(c *config) {
+		c.exactNumbers = true
+	}
+}
+
+// WithLenient makes Describe pass over malformed members of the top-level object or array, recording each in JsonDescription.Skipped and describing the rest, instead of failing the whole document
+//
+// A malformed member ends at the next comma or closing bracket outside its strings and brackets, so one with an unterminated string or unbalanced brackets can take its neighbors with it. A document that is not a container, or whose container never closes, still fails, as do limits.
+func WithLenient() Option {
+	return func(c *config) {
+		c.lenient = true
+	}
+}
+
+// WithHeuristics makes Describe recognize top-level forms by the rules registered in h before treating data as plain JSON, describing the JSON a rule finds wrapped, or else reporting the type the rule gives with no members
+//
+// Renamed types apply only to h's own TypeOf and Name, so descriptions keep the usual names that Friendly and Diff rely on.
+func WithHeuristics(h *Heuristics) Option {
+	return func(c *config) {
+		c.heuristics = h
+	}
+}
+
+// WithDetectors makes Describe ask each detector in turn whether it recognizes a top-level member, counting those recognized in JsonDescription.Detected under the first detector's name for Friendly to report
+//
+// A detector sees every member, of any type, as written, so one can recognize numbers as well as strings.
+func WithDetectors(detectors ...Detector) Option {
+	return func(c *config) {
+		c.detectors = append(c.detectors, detectors...)
+	}
+}
+
+// WithStringFormats makes Describe recognize dates, timestamps, UUIDs, email addresses, and URLs among string members, counting them in JsonDescription.Formats for Friendly to report
+func WithStringFormats() Option {
+	return func(c *config) {
+		c.stringFormats = true
+	}
+}
+
+// WithNesting makes Describe also describe the members of nested objects and arrays, levels deep, in JsonDescription.Nested, and limits Friendly to mentioning that many levels
+//
+// Each level is scanned again when it is described, so deep nesting costs time in proportion to levels.
+func WithNesting(levels int) Option {
+	return func(c *config) {
+		c.nesting = levels
+	}
+}
+
+// How many levels of JsonDescription.Nested Friendly may mention; all of them unless WithNesting says otherwise
+func (c *config) friendlyDepth() int {
+	if c.nesting > 0 {
+		return c.nesting
+	}
+
+	return int(^uint(0) >> 1)
+}
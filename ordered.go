@@ -0,0 +1,240 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// A single key/value pair of an object, as it appeared in the document
+type member struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Splits a raw JSON object into its members in document order
+func orderedMembers(data []byte) ([]member, error) {
+	var (
+		dec     = json.NewDecoder(bytes.NewReader(data))
+		members = make([]member, 0)
+	)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return members, fmt.Errorf("not a json object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return members, err
+		}
+
+		var val json.RawMessage
+
+		if err = dec.Decode(&val); err != nil {
+			return members, err
+		}
+
+		members = append(members, member{Key: tok.(string), Value: val})
+	}
+
+	return members, nil
+}
+
+// Serializes members as a JSON object in the order given
+func marshalMembers(members []member) json.RawMessage {
+	var out bytes.Buffer
+
+	out.WriteByte('{')
+
+	for i, m := range members {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		k, _ := json.Marshal(m.Key)
+		out.Write(k)
+		out.WriteByte(':')
+		out.Write(m.Value)
+	}
+
+	out.WriteByte('}')
+	return out.Bytes()
+}
+
+// A container for json.RawMessage from an object that remembers the order its members appeared in
+//
+// The zero value is an empty object ready to use.
+type OrderedRawObject struct {
+	members []member
+	index   map[string]int
+}
+
+// UnmarshalOrderedObject is a convenience function parsing a JSON object into a new OrderedRawObject
+func UnmarshalOrderedObject(in []byte) (*OrderedRawObject, error) {
+	var (
+		obj = new(OrderedRawObject)
+		typ *string
+		err error
+	)
+
+	in = bytes.TrimSpace(in)
+	typ, err = TypeOf(in)
+
+	if err != nil {
+		return obj, err
+	}
+
+	if *typ != "object" {
+		err = &ErrUnexpectedType{Want: Object, Got: ParseJsonType(*typ)}
+		return obj, err
+	}
+
+	err = obj.UnmarshalJSON(in)
+	return obj, err
+}
+
+// Implements json.Unmarshaler; a repeated key keeps its first position and its last value, as json.Unmarshal would
+func (o *OrderedRawObject) UnmarshalJSON(data []byte) error {
+	members, err := orderedMembers(bytes.TrimSpace(data))
+
+	if err != nil {
+		return err
+	}
+
+	o.members, o.index = nil, nil
+
+	for _, m := range members {
+		o.Set(m.Key, m.Value)
+	}
+
+	return nil
+}
+
+// Implements json.Marshaler, writing members in order
+func (o OrderedRawObject) MarshalJSON() ([]byte, error) {
+	for _, m := range o.members {
+		if !json.Valid(m.Value) {
+			return nil, fmt.Errorf("value for key %q: %w", m.Key, ErrInvalidJson)
+		}
+	}
+
+	return marshalMembers(o.members), nil
+}
+
+// Len returns the number of members
+func (o *OrderedRawObject) Len() int {
+	return len(o.members)
+}
+
+// Value returns the raw value stored under key, and whether it is present
+func (o *OrderedRawObject) Value(key string) (json.RawMessage, bool) {
+	i, ok := o.index[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	return o.members[i].Value, true
+}
+
+// Set stores value under key, keeping an existing key in place and appending a new one
+func (o *OrderedRawObject) Set(key string, value json.RawMessage) {
+	if i, ok := o.index[key]; ok {
+		o.members[i].Value = value
+		return
+	}
+
+	if o.index == nil {
+		o.index = make(map[string]int)
+	}
+
+	o.index[key] = len(o.members)
+	o.members = append(o.members, member{Key: key, Value: value})
+}
+
+// Delete removes the member stored under key, reporting whether it was present; later members keep their relative order
+func (o *OrderedRawObject) Delete(key string) bool {
+	i, ok := o.index[key]
+
+	if !ok {
+		return false
+	}
+
+	o.members = append(o.members[:i], o.members[i+1:]...)
+	delete(o.index, key)
+
+	for j := i; j < len(o.members); j++ {
+		o.index[o.members[j].Key] = j
+	}
+
+	return true
+}
+
+// Range calls fn for each member in order, stopping early if fn returns false
+func (o *OrderedRawObject) Range(fn func(key string, value json.RawMessage) bool) {
+	for _, m := range o.members {
+		if !fn(m.Key, m.Value) {
+			return
+		}
+	}
+}
+
+// RawObject copies the members into an unordered RawObject
+func (o *OrderedRawObject) RawObject() *RawObject {
+	obj := make(RawObject, len(o.members))
+
+	for _, m := range o.members {
+		obj[m.Key] = m.Value
+	}
+
+	return &obj
+}
+
+// this.Compare(that) is RawObject.Compare with each category listed in document order instead of sorted: added keys in that's order, the rest in this one's
+//
+// Honors the same options as RawObject.Compare.
+func (o *OrderedRawObject) Compare(n *OrderedRawObject, opts ...Option) *DiffResult {
+	diff := o.RawObject().Compare(n.RawObject(), opts...)
+
+	// Keys respelled under WithCaseInsensitiveKeys or WithKeyNormalizer are listed by their new spelling, so are placed by their old one
+	was := make(map[string]string, len(diff.Respelled))
+	for _, p := range diff.Respelled {
+		was[p.New] = p.Old
+	}
+
+	inOrder := func(keys []string, order map[string]int) {
+		pos := func(k string) int {
+			if i, ok := order[k]; ok {
+				return i
+			}
+			return order[was[k]]
+		}
+
+		sort.SliceStable(keys, func(i, j int) bool {
+			return pos(keys[i]) < pos(keys[j])
+		})
+	}
+
+	inOrder(diff.Added, n.index)
+	inOrder(diff.Deleted, o.index)
+	inOrder(diff.Modified, o.index)
+	inOrder(diff.Replaced, o.index)
+	inOrder(diff.TypeChanged, o.index)
+
+	for _, pairs := range [][]KeyPair{diff.Respelled, diff.Renamed} {
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return o.index[pairs[i].Old] < o.index[pairs[j].Old]
+		})
+	}
+
+	return diff
+}
+
+// this.Diff(that) is RawObject.Diff with each category listed in document order instead of sorted: added keys in that's order, the rest in this one's
+//
+// Deprecated: Use Compare.
+func (o *OrderedRawObject) Diff(n *OrderedRawObject, opts ...Option) map[string][]string {
+	return o.Compare(n, opts...).Map()
+}
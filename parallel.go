@@ -0,0 +1,186 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+)
+
+// Top-level arrays smaller than this are described sequentially, as splitting them would cost more than it saves
+const parallelMinBytes = 1 << 20
+
+// How many commas a worker tries before giving up on finding where an element begins
+const maxResyncAttempts = 64
+
+// The outcome of scanning a run of elements of the top-level array
+type shardResult struct {
+	// Offset of the comma the run began after, or of the opening bracket for the first run
+	start int
+	// Offset of the comma the run stopped at, or -1 if it reached the closing bracket
+	next   int
+	counts [len(jsonTypeNames)]uint
+	// Offset just past the closing bracket, when the run reached it
+	end int
+	ok  bool
+}
+
+// How many workers WithParallelism asks for
+func (c *config) workers() int {
+	if c.parallelism > 0 {
+		return c.parallelism
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// Types the elements of a large top-level array on several goroutines, adding them to the counts
+//
+// The array is cut at evenly spaced offsets. Each worker guesses where the first element after its offset begins, and is checked against the worker before it, which knows exactly where its own run ended; a wrong guess is rescanned. Returns false when data is not a well-formed array or a guard trips, leaving the sequential scan to report the exact error.
+func (d *Describer) describeArrayParallel(data []byte) bool {
+	var (
+		n      = d.cfg.workers()
+		open   = bytes.IndexByte(data, '[')
+		cuts   = make([]int, n+1)
+		shards = make([]shardResult, n)
+		wg     sync.WaitGroup
+	)
+
+	if open < 0 || !blank(data[:open]) {
+		return false
+	}
+
+	for k := range cuts {
+		cuts[k] = open + (len(data)-open)*k/n
+	}
+
+	cuts[n] = len(data)
+
+	for k := 0; k < n; k++ {
+		wg.Add(1)
+
+		go func(k int) {
+			defer wg.Done()
+
+			if k == 0 {
+				shards[k] = d.scanShard(data, open, cuts[1])
+				return
+			}
+
+			shards[k] = d.resync(data, cuts[k], cuts[k+1])
+		}(k)
+	}
+
+	wg.Wait()
+
+	var (
+		counts [len(jsonTypeNames)]uint
+		total  uint
+		at     = open
+	)
+
+	for k := 0; k < n && at >= 0; k++ {
+		// The previous run ended somewhere other than this one's guess, so redo it from the right place
+		if !shards[k].ok || shards[k].start != at {
+			shards[k] = d.scanShard(data, at, cuts[k+1])
+		}
+
+		if !shards[k].ok {
+			return false
+		}
+
+		for t, c := range shards[k].counts {
+			counts[t] += c
+			total += c
+		}
+
+		if at = shards[k].next; at < 0 && !blank(data[shards[k].end:]) {
+			return false
+		}
+	}
+
+	if at >= 0 {
+		// The last worker stops at the end of the data, so this cannot happen for a valid array
+		return false
+	}
+
+	if d.cfg.maxMembers > 0 && total > uint(d.cfg.maxMembers) {
+		return false
+	}
+
+	d.counts = counts
+	return true
+}
+
+// Tries successive commas from offset from until one begins a run that scans cleanly
+func (d *Describer) resync(data []byte, from, limit int) shardResult {
+	for attempt := 0; attempt < maxResyncAttempts; attempt++ {
+		i := bytes.IndexByte(data[from:], ',')
+
+		if i < 0 {
+			break
+		}
+
+		res := d.scanShard(data, from+i, limit)
+
+		// A run that closes the array too early is most likely inside a nested container
+		if res.ok && (res.next >= 0 || blank(data[res.end:])) {
+			return res
+		}
+
+		from += i + 1
+	}
+
+	return shardResult{}
+}
+
+// Scans elements of the top-level array from just after the comma or bracket at start, stopping at the first comma at or past limit
+func (d *Describer) scanShard(data []byte, start, limit int) shardResult {
+	var (
+		res = shardResult{start: start, next: -1}
+		s   = scanner{data: data, pos: start + 1, depth: 1, cfg: d.cfg}
+	)
+
+	if s.skipSpace(); start >= 0 && data[start] == '[' && s.pos < len(data) && data[s.pos] == ']' {
+		res.end, res.ok = s.pos+1, true
+		return res
+	}
+
+	for {
+		typ, err := s.value("", nil)
+		if err != nil {
+			return res
+		}
+
+		res.counts[typ]++
+
+		if s.skipSpace(); s.pos >= len(data) {
+			return res
+		}
+
+		switch data[s.pos] {
+		case ']':
+			res.end, res.ok = s.pos+1, true
+			return res
+		case ',':
+			if s.pos >= limit {
+				res.next, res.ok = s.pos, true
+				return res
+			}
+			s.pos++
+		default:
+			return res
+		}
+	}
+}
+
+func blank(b []byte) bool {
+	for _, c := range b {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Builds a top-level array of n copies of elem, large enough for the parallel scan once n*len(elem) passes parallelMinBytes
+func repeatedArray(elem string, n int) []byte {
+	var b bytes.Buffer
+
+	b.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(elem)
+	}
+	b.WriteByte(']')
+
+	return b.Bytes()
+}
+
+// The shard scanners carry no context, and must not need one
+func TestDescribeParallelContainers(t *testing.T) {
+	data := repeatedArray(`{"a":1,"b":"xxxxxxxx"}`, 60000)
+
+	if len(data) < parallelMinBytes {
+		t.Fatalf("test input is %d bytes, below parallelMinBytes", len(data))
+	}
+
+	descr, err := Describe(data, WithParallelism(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if descr.Element != "array" || descr.Members["object"] != 60000 || len(descr.Members) != 1 {
+		t.Errorf("got %s %v, want array map[object:60000]", descr.Element, descr.Members)
+	}
+}
+
+// Sharding must not change what Describe reports, for well-formed input or for input broken past the first shard
+func TestDescribeParallelMatchesSequential(t *testing.T) {
+	var (
+		mixed  = repeatedArray(`{"a":[1,{"b":"x,]"}],"c":null},[[],["y,[",2]],"s",3`, 30000)
+		broken = append([]byte(nil), mixed...)
+	)
+
+	// Corrupt an element well into the last quarter of the array
+	at := len(broken) * 7 / 8
+	at += bytes.IndexByte(broken[at:], ':')
+	broken[at] = ';'
+
+	tests := []struct {
+		name    string
+		data    []byte
+		invalid bool
+	}{
+		{"objects", repeatedArray(`{"a":1,"b":"xxxxxxxx"}`, 60000), false},
+		{"nested arrays", repeatedArray(`[[1,2],[3,[4,"]"]]]`, 60000), false},
+		{"mixed", mixed, false},
+		{"malformed in a later shard", broken, true},
+		{"truncated", mixed[:len(mixed)-1], true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.data) < parallelMinBytes {
+				t.Fatalf("test input is %d bytes, below parallelMinBytes", len(tt.data))
+			}
+
+			want, wantErr := Describe(tt.data)
+			got, gotErr := Describe(tt.data, WithParallelism(4))
+
+			if tt.invalid != errors.Is(wantErr, ErrInvalidJson) {
+				t.Fatalf("sequential Describe returned %v", wantErr)
+			}
+
+			if (wantErr == nil) != (gotErr == nil) || wantErr != nil && wantErr.Error() != gotErr.Error() {
+				t.Fatalf("got error %v, want %v", gotErr, wantErr)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package jsondescriber
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Selects how nested locations are written as strings
+type PathStyle int
+
+const (
+	// JSON Pointer per RFC 6901, e.g. "/user/tags/0"
+	PointerPath PathStyle = iota
+	// Dotted keys with bracketed indices, e.g. "user.tags[0]"; awkward keys are quoted as ["a.b"]
+	DottedPath
+)
+
+// Escapes a single reference token per RFC 6901: "~" becomes "~0" and "/" becomes "~1"
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// Appends an object key to a path
+func joinKey(style PathStyle, parent, key string) string {
+	if style == PointerPath {
+		return parent + "/" + escapePointer(key)
+	}
+
+	if key == "" || strings.ContainsAny(key, `.[]"`) {
+		return parent + "[" + strconv.Quote(key) + "]"
+	}
+
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
+
+// Appends an array index to a path
+func joinIndex(style PathStyle, parent string, i int) string {
+	if style == PointerPath {
+		return parent + "/" + strconv.Itoa(i)
+	}
+
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
+
+// Appends a wildcard standing for every index of an array, e.g. "/items/*" or "items[*]"
+func joinWildcard(style PathStyle, parent string) string {
+	if style == PointerPath {
+		return parent + "/*"
+	}
+
+	return parent + "[*]"
+}
+
+// Splits a JSON Pointer into its unescaped reference tokens; "" refers to the whole document
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("json pointer %q must begin with /", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+
+	for i, tok := range tokens {
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || (tok[j+1] != '0' && tok[j+1] != '1')) {
+				return nil, fmt.Errorf("json pointer %q has an invalid ~ escape", ptr)
+			}
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+	}
+
+	return tokens, nil
+}
+
+// Parses an array index token, which may not have leading zeros; "-" (one past the end) is only accepted when allowEnd is set
+func arrayIndex(tok string, length int, allowEnd bool) (int, error) {
+	if allowEnd && tok == "-" {
+		return length, nil
+	}
+
+	i, err := strconv.Atoi(tok)
+
+	if err != nil || i < 0 || (len(tok) > 1 && tok[0] == '0') || tok[0] == '+' {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	if i > length || (i == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+
+	return i, nil
+}
+
+// Get returns the raw value at a JSON Pointer, e.g. "/user/tags/0"
+func (o *RawObject) Get(pointer string) (json.RawMessage, error) {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return json.Marshal(o)
+	}
+
+	val, ok := (*o)[tokens[0]]
+
+	if !ok {
+		return nil, fmt.Errorf("no member at %s", pointer)
+	}
+
+	at := joinKey(PointerPath, "", tokens[0])
+
+	for _, tok := range tokens[1:] {
+		typ, _ := TypeOf(val)
+
+		switch *typ {
+		case "object":
+			obj := make(RawObject)
+			json.Unmarshal(val, &obj)
+			if val, ok = obj[tok]; !ok {
+				return nil, fmt.Errorf("no member at %s", pointer)
+			}
+		case "array":
+			arr := make(RawArray, 0)
+			json.Unmarshal(val, &arr)
+			idx, err := arrayIndex(tok, len(arr), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", at, err)
+			}
+			val = arr[idx]
+		default:
+			return nil, fmt.Errorf("cannot descend into %s at %s", *typ, at)
+		}
+
+		at = joinKey(PointerPath, at, tok)
+	}
+
+	return val, nil
+}
+
+// Set stores value at a JSON Pointer, replacing any existing member or element; an index of "-" or the array's length appends
+//
+// Missing intermediate objects are an error unless WithCreateParents is given.
+func (o *RawObject) Set(pointer string, value json.RawMessage, opts ...Option) error {
+	cfg := newConfig(opts)
+	tokens, err := parsePointer(pointer)
+
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot set the root of a RawObject")
+	}
+
+	if !json.Valid(value) {
+		return fmt.Errorf("value for %s: %w", pointer, ErrInvalidJson)
+	}
+
+	if *o == nil {
+		*o = make(RawObject)
+	}
+
+	obj := *o
+	key := tokens[0]
+
+	if len(tokens) == 1 {
+		obj[key] = value
+		return nil
+	}
+
+	cur, ok := obj[key]
+
+	if !ok {
+		if !cfg.createParents {
+			return fmt.Errorf("no member at /%s", escapePointer(key))
+		}
+		cur = json.RawMessage(`{}`)
+	}
+
+	updated, err := setIn(cur, tokens[1:], "/"+escapePointer(key), value, cfg.createParents)
+
+	if err != nil {
+		return err
+	}
+
+	obj[key] = updated
+	return nil
+}
+
+// Returns a copy of raw with value stored at the path given by tokens; at is the pointer to raw, for errors
+func setIn(raw json.RawMessage, tokens []string, at string, value json.RawMessage, create bool) (json.RawMessage, error) {
+	typ, _ := TypeOf(raw)
+	tok, last := tokens[0], len(tokens) == 1
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		i := 0
+		for i < len(members) && members[i].Key != tok {
+			i++
+		}
+
+		if i == len(members) {
+			if !last && !create {
+				return nil, fmt.Errorf("no member at %s", joinKey(PointerPath, at, tok))
+			}
+			members = append(members, member{Key: tok, Value: json.RawMessage(`{}`)})
+		}
+
+		if last {
+			members[i].Value = value
+		} else if members[i].Value, err = setIn(members[i].Value, tokens[1:], joinKey(PointerPath, at, tok), value, create); err != nil {
+			return nil, err
+		}
+
+		return marshalMembers(members), nil
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return nil, err
+		}
+
+		i, err := arrayIndex(tok, len(arr), last)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", at, err)
+		}
+
+		if i == len(arr) {
+			arr = append(arr, value)
+		} else if last {
+			arr[i] = value
+		} else if arr[i], err = setIn(arr[i], tokens[1:], joinIndex(PointerPath, at, i), value, create); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(arr)
+	}
+
+	return nil, fmt.Errorf("cannot descend into %s at %s", *typ, at)
+}
+
+// Delete removes the member or element at a JSON Pointer, reporting whether anything was removed
+func (o *RawObject) Delete(pointer string) bool {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil || len(tokens) == 0 {
+		return false
+	}
+
+	obj := *o
+	cur, ok := obj[tokens[0]]
+
+	if !ok {
+		return false
+	}
+
+	if len(tokens) == 1 {
+		delete(obj, tokens[0])
+		return true
+	}
+
+	updated, removed := deleteIn(cur, tokens[1:])
+
+	if removed {
+		obj[tokens[0]] = updated
+	}
+
+	return removed
+}
+
+// Delete removes the element or nested member at a JSON Pointer, reporting whether anything was removed; later elements shift down
+func (a *RawArray) Delete(pointer string) bool {
+	tokens, err := parsePointer(pointer)
+
+	if err != nil || len(tokens) == 0 {
+		return false
+	}
+
+	arr := *a
+	i, err := arrayIndex(tokens[0], len(arr), false)
+
+	if err != nil {
+		return false
+	}
+
+	if len(tokens) == 1 {
+		*a = append(arr[:i], arr[i+1:]...)
+		return true
+	}
+
+	updated, removed := deleteIn(arr[i], tokens[1:])
+
+	if removed {
+		arr[i] = updated
+	}
+
+	return removed
+}
+
+// Returns a copy of raw without the value at the path given by tokens, and whether it was there
+func deleteIn(raw json.RawMessage, tokens []string) (json.RawMessage, bool) {
+	typ, _ := TypeOf(raw)
+	tok, last := tokens[0], len(tokens) == 1
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return raw, false
+		}
+
+		for i := range members {
+			if members[i].Key != tok {
+				continue
+			}
+
+			if last {
+				return marshalMembers(append(members[:i], members[i+1:]...)), true
+			}
+
+			updated, removed := deleteIn(members[i].Value, tokens[1:])
+			if !removed {
+				return raw, false
+			}
+
+			members[i].Value = updated
+			return marshalMembers(members), true
+		}
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return raw, false
+		}
+
+		if (&arr).Delete(joinPointer(tokens)) {
+			out, _ := json.Marshal(arr)
+			return out, true
+		}
+	}
+
+	return raw, false
+}
+
+// Reassembles reference tokens into a JSON Pointer
+func joinPointer(tokens []string) string {
+	var ptr string
+
+	for _, tok := range tokens {
+		ptr = joinKey(PointerPath, ptr, tok)
+	}
+
+	return ptr
+}
@@ -0,0 +1,127 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Categorizes how a number changes when read into a float64
+type PrecisionLossKind string
+
+const (
+	// An integer beyond float64's exact range, such as a 64-bit ID, rounds to a neighbor
+	PrecisionInteger PrecisionLossKind = "integer"
+	// A fraction or exponent with more significant digits than float64 holds rounds them away
+	PrecisionDecimal PrecisionLossKind = "decimal"
+	// The magnitude is too large for float64 and becomes ±Inf
+	PrecisionOverflow PrecisionLossKind = "overflow"
+	// The magnitude is too small for float64 and becomes zero
+	PrecisionUnderflow PrecisionLossKind = "underflow"
+)
+
+// Reports a number found by CheckPrecision that reads back differently after a round trip through float64, located by its JSON Pointer
+type PrecisionLoss struct {
+	Path string
+	// The number as written
+	Literal string
+	// The number as float64 holds it, written in full for integers
+	Rounded string
+	Kind    PrecisionLossKind
+}
+
+// Walks a document and reports every number that a float64 cannot hold exactly, which json.Unmarshal into interface{} and most other languages' JSON parsers would silently round
+//
+// A number counts as lost only if its value changes, so 0.1 and 1.50 pass, since float64 reads them back as the same decimal, while 9007199254740993 and 0.12345678901234567890 do not. Results are in document order.
+func CheckPrecision(data []byte) ([]PrecisionLoss, error) {
+	var (
+		losses = make([]PrecisionLoss, 0)
+		dec    = json.NewDecoder(bytes.NewReader(data))
+	)
+
+	if _, err := TypeOf(bytes.TrimSpace(data)); err != nil {
+		return losses, err
+	}
+
+	dec.UseNumber()
+
+	err := checkPrecision(dec, "", &losses)
+	return losses, err
+}
+
+// Consumes one value from dec, recording numbers within it that float64 would change
+func checkPrecision(dec *json.Decoder, ptr string, losses *[]PrecisionLoss) error {
+	tok, err := dec.Token()
+
+	if err != nil {
+		return err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err = checkPrecision(dec, joinKey(PointerPath, ptr, key.(string)), losses); err != nil {
+					return err
+				}
+			}
+		} else {
+			for i := 0; dec.More(); i++ {
+				if err = checkPrecision(dec, joinIndex(PointerPath, ptr, i), losses); err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err = dec.Token()
+
+	case json.Number:
+		if loss, lost := precisionLoss(string(tok)); lost {
+			loss.Path = ptr
+			*losses = append(*losses, loss)
+		}
+	}
+
+	return err
+}
+
+// Reads a number literal into a float64 and back, saying how it changed if it did
+func precisionLoss(lit string) (PrecisionLoss, bool) {
+	var (
+		loss    = PrecisionLoss{Literal: lit}
+		integer = !strings.ContainsAny(lit, ".eE")
+		// A syntax error cannot happen past TypeOf, and a range error leaves ±Inf, which is reported below
+		f, _ = strconv.ParseFloat(lit, 64)
+	)
+
+	exact, ok := parseDecimal(lit)
+
+	switch {
+	case math.IsInf(f, 0):
+		loss.Kind, loss.Rounded = PrecisionOverflow, strconv.FormatFloat(f, 'g', -1, 64)
+		return loss, true
+	case !ok:
+		// An exponent beyond int64 that did not overflow can only have underflowed, which loses something unless the digits were all zero
+		mantissa, _, _ := strings.Cut(strings.ToLower(lit), "e")
+		loss.Kind, loss.Rounded = PrecisionUnderflow, "0"
+		return loss, strings.Trim(mantissa, "-0.") != ""
+	case f == 0:
+		loss.Kind, loss.Rounded = PrecisionUnderflow, "0"
+		return loss, exact.digits != ""
+	}
+
+	if integer {
+		loss.Kind, loss.Rounded = PrecisionInteger, strconv.FormatFloat(f, 'f', -1, 64)
+	} else {
+		loss.Kind, loss.Rounded = PrecisionDecimal, strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	back, _ := parseDecimal(loss.Rounded)
+	return loss, back.cmp(exact) != 0
+}
@@ -0,0 +1,158 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Selects values for Redact to mask; every criterion that is set must match
+type RedactRule struct {
+	// Glob matched against the member's key, e.g. "*password*"
+	Key string
+	// Glob matched against the value's JSON Pointer, e.g. "/users/*/ssn"
+	Path string
+	// Regular expression matched against the value's JSON Pointer
+	Pattern *regexp.Regexp
+	// Raw JSON written in place of the value; when empty, the value keeps its shape and type
+	Placeholder json.RawMessage
+}
+
+// Stands in for redacted strings when a rule has no Placeholder
+const redactedString = `"[REDACTED]"`
+
+// Replaces values matched by any of the rules with placeholders, leaving the rest of the document intact
+func Redact(data []byte, rules ...RedactRule) ([]byte, error) {
+	var out bytes.Buffer
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		if err := rules[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	err := redactValue(&out, bytes.TrimSpace(data), "", nil, rules, false)
+	return out.Bytes(), err
+}
+
+func (r *RedactRule) validate() error {
+	if r.Key == "" && r.Path == "" && r.Pattern == nil {
+		return fmt.Errorf("redact rule matches nothing")
+	}
+
+	if _, err := path.Match(r.Key, ""); err != nil {
+		return fmt.Errorf("bad redact key glob %q: %w", r.Key, err)
+	}
+
+	if _, err := path.Match(r.Path, ""); err != nil {
+		return fmt.Errorf("bad redact path glob %q: %w", r.Path, err)
+	}
+
+	if len(r.Placeholder) > 0 && !json.Valid(r.Placeholder) {
+		return fmt.Errorf("redact placeholder: %w", ErrInvalidJson)
+	}
+
+	return nil
+}
+
+// Reports whether the rule applies to the value at ptr; key is nil for array elements and the root
+func (r *RedactRule) matches(ptr string, key *string) bool {
+	if r.Key != "" {
+		if key == nil {
+			return false
+		}
+		if ok, _ := path.Match(r.Key, *key); !ok {
+			return false
+		}
+	}
+
+	if r.Path != "" {
+		if ok, _ := path.Match(r.Path, ptr); !ok {
+			return false
+		}
+	}
+
+	if r.Pattern != nil && !r.Pattern.MatchString(ptr) {
+		return false
+	}
+
+	return true
+}
+
+// Writes raw to out, masking it if a rule matches; once masked, every scalar beneath is masked too
+func redactValue(out *bytes.Buffer, raw json.RawMessage, ptr string, key *string, rules []RedactRule, masked bool) error {
+	if !masked {
+		for i := range rules {
+			if rules[i].matches(ptr, key) {
+				if len(rules[i].Placeholder) > 0 {
+					return json.Compact(out, rules[i].Placeholder)
+				}
+				masked = true
+				break
+			}
+		}
+	}
+
+	typ, _ := TypeOf(raw)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+
+		out.WriteByte('{')
+		for i := range members {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			k, _ := json.Marshal(members[i].Key)
+			out.Write(k)
+			out.WriteByte(':')
+			if err = redactValue(out, members[i].Value, joinKey(PointerPath, ptr, members[i].Key), &members[i].Key, rules, masked); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+		return nil
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+
+		out.WriteByte('[')
+		for i := range arr {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := redactValue(out, arr[i], joinIndex(PointerPath, ptr, i), nil, rules, masked); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+
+	// Literals reveal nothing beyond the type, which a description reports anyway
+	case "string":
+		if masked {
+			out.WriteString(redactedString)
+			return nil
+		}
+	case "number":
+		if masked {
+			out.WriteString("0")
+			return nil
+		}
+	}
+
+	return json.Compact(out, raw)
+}
@@ -0,0 +1,135 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ANSI escapes for each kind of change
+const (
+	ansiGreen  = "\x1b[32m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// Writes the changes to w, one key per line: "+ key: new" when added, "- key: old" when deleted, "~ key: old → new" when modified, "~ key: old → new (replaced)" when replaced under WithReplaceThreshold, "~ key: old (string) → new (number)" when the type changed, "~ oldKey → key (respelled)" when the key matched under WithCaseInsensitiveKeys or WithKeyNormalizer, and "~ oldKey → key: value (renamed)" when paired under WithRenameDetection
+//
+// Lines are green, red, and yellow when w is a terminal and NO_COLOR is unset, and plain otherwise. Honors WithColor.
+func (d *DetailedDiff) Render(w io.Writer, opts ...Option) error {
+	var (
+		cfg = newConfig(opts)
+		out = &diffWriter{w: w, color: cfg.colorFor(w)}
+	)
+
+	for _, c := range d.Added {
+		out.line(ansiGreen, "+ %s: %s", SafeKey(c.Key), c.New)
+	}
+
+	for _, c := range d.Deleted {
+		out.line(ansiRed, "- %s: %s", SafeKey(c.Key), c.Old)
+	}
+
+	for _, c := range d.Modified {
+		out.line(ansiYellow, "~ %s: %s → %s", SafeKey(c.Key), c.Old, c.New)
+	}
+
+	for _, c := range d.Replaced {
+		out.line(ansiYellow, "~ %s: %s → %s (replaced)", SafeKey(c.Key), c.Old, c.New)
+	}
+
+	for _, c := range d.TypeChanged {
+		out.line(ansiYellow, "~ %s: %s (%s) → %s (%s)", SafeKey(c.Key), c.Old, c.OldType, c.New, c.NewType)
+	}
+
+	for _, c := range d.Respelled {
+		out.line(ansiYellow, "~ %s → %s (respelled)", SafeKey(c.OldKey), SafeKey(c.Key))
+	}
+
+	for _, c := range d.Renamed {
+		out.line(ansiYellow, "~ %s → %s: %s (renamed)", SafeKey(c.OldKey), SafeKey(c.Key), c.New)
+	}
+
+	return out.err
+}
+
+// Writes the changed keys to w as DetailedDiff.Render does, without values
+//
+// Honors WithColor.
+func (r *DiffResult) Render(w io.Writer, opts ...Option) error {
+	var (
+		cfg = newConfig(opts)
+		out = &diffWriter{w: w, color: cfg.colorFor(w)}
+	)
+
+	for _, k := range r.Added {
+		out.line(ansiGreen, "+ %s", SafeKey(k))
+	}
+
+	for _, k := range r.Deleted {
+		out.line(ansiRed, "- %s", SafeKey(k))
+	}
+
+	for _, k := range r.Modified {
+		out.line(ansiYellow, "~ %s", SafeKey(k))
+	}
+
+	for _, k := range r.Replaced {
+		out.line(ansiYellow, "~ %s (replaced)", SafeKey(k))
+	}
+
+	for _, k := range r.TypeChanged {
+		out.line(ansiYellow, "~ %s (type changed)", SafeKey(k))
+	}
+
+	for _, p := range r.Respelled {
+		out.line(ansiYellow, "~ %s → %s (respelled)", SafeKey(p.Old), SafeKey(p.New))
+	}
+
+	for _, p := range r.Renamed {
+		out.line(ansiYellow, "~ %s → %s (renamed)", SafeKey(p.Old), SafeKey(p.New))
+	}
+
+	return out.err
+}
+
+// Writes lines, optionally colored, keeping the first error
+type diffWriter struct {
+	w     io.Writer
+	color bool
+	err   error
+}
+
+func (d *diffWriter) line(color, format string, args ...interface{}) {
+	if d.err != nil {
+		return
+	}
+
+	text := fmt.Sprintf(format, args...)
+
+	if d.color {
+		text = color + text + ansiReset
+	}
+
+	_, d.err = fmt.Fprintln(d.w, text)
+}
+
+// Decides whether output to w is colored: as WithColor says if given, otherwise only on a terminal with NO_COLOR unset
+func (c *config) colorFor(w io.Writer) bool {
+	if c.colorSet {
+		return c.color
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,86 @@
+package jsondescriber
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Every category of a DiffResult must show up in Render, so that a new one cannot be added without being rendered
+func TestDiffResultRenderCoversEveryField(t *testing.T) {
+	var (
+		r    DiffResult
+		v    = reflect.ValueOf(&r).Elem()
+		want = make([]string, 0)
+	)
+
+	for i := 0; i < v.NumField(); i++ {
+		var (
+			f    = v.Field(i)
+			name = v.Type().Field(i).Name
+		)
+
+		switch f.Interface().(type) {
+		case []string:
+			f.Set(reflect.ValueOf([]string{"key" + name}))
+			want = append(want, "key"+name)
+		case []KeyPair:
+			f.Set(reflect.ValueOf([]KeyPair{{Old: "old" + name, New: "new" + name}}))
+			want = append(want, "old"+name, "new"+name)
+		case map[string]KeyPresence:
+			// Describes the keys of the other fields rather than listing any of its own
+		default:
+			t.Fatalf("DiffResult.%s has type %s, which the test does not know how to fill", name, f.Type())
+		}
+	}
+
+	var out strings.Builder
+	if err := r.Render(&out, WithColor(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range want {
+		if !strings.Contains(out.String(), key) {
+			t.Errorf("Render left out %s:\n%s", key, out.String())
+		}
+	}
+}
+
+// Likewise for every category of a DetailedDiff, in Render and SideBySide
+func TestDetailedDiffRenderCoversEveryField(t *testing.T) {
+	var (
+		d    DetailedDiff
+		v    = reflect.ValueOf(&d).Elem()
+		want = make([]string, 0)
+	)
+
+	for i := 0; i < v.NumField(); i++ {
+		var (
+			f    = v.Field(i)
+			name = v.Type().Field(i).Name
+		)
+
+		if _, ok := f.Interface().([]ValueChange); !ok {
+			t.Fatalf("DetailedDiff.%s has type %s, which the test does not know how to fill", name, f.Type())
+		}
+
+		f.Set(reflect.ValueOf([]ValueChange{{Key: "key" + name, OldKey: "old" + name, Old: "1", New: "2"}}))
+		want = append(want, "key"+name)
+	}
+
+	var out strings.Builder
+	if err := d.Render(&out, WithColor(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	side := d.SideBySide()
+
+	for _, key := range want {
+		if !strings.Contains(out.String(), key) {
+			t.Errorf("Render left out %s:\n%s", key, out.String())
+		}
+		if !strings.Contains(side, key) {
+			t.Errorf("SideBySide left out %s:\n%s", key, side)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Collects what is known about a document for rendering as a human-readable report
+type Report struct {
+	Title       string
+	Description *JsonDescription
+	// Path:type pairs for every nested member, sorted by path; empty for scalars
+	Keys []KeyType
+	// The document's structure in document order
+	Tree *ReportNode
+}
+
+// One element of a document's structure tree
+type ReportNode struct {
+	// The member's key, or its index within an array; empty for the root
+	Name     string
+	Type     string
+	Children []*ReportNode
+}
+
+// Describes a raw JSON document and inventories its nested members for reporting
+//
+// Honors WithPathStyle.
+func NewReport(title string, data []byte, opts ...Option) (*Report, error) {
+	var (
+		cfg    = newConfig(opts)
+		inv    = make(map[string]string)
+		report = &Report{Title: title}
+		err    error
+	)
+
+	data = bytes.TrimSpace(data)
+
+	if report.Description, err = Describe(data); err != nil {
+		return report, err
+	}
+
+	if report.Tree, err = reportTree("", data); err != nil {
+		return report, err
+	}
+
+	switch report.Description.Element {
+	case "object":
+		obj := make(RawObject)
+		json.Unmarshal(data, &obj)
+		report.Keys = obj.DeepInventorySorted(opts...)
+		return report, nil
+	case "array":
+		arr := make(RawArray, 0)
+		json.Unmarshal(data, &arr)
+		for i := range arr {
+			deepInventory(arr[i], joinIndex(cfg.pathStyle, "", i), cfg.pathStyle, inv)
+		}
+	}
+
+	report.Keys = sortInventory(inv)
+	return report, nil
+}
+
+// Renders the report as Markdown with a summary sentence and tables of member types and keys
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	if r.Title != "" {
+		fmt.Fprintf(&sb, "# %s\n\n", r.Title)
+	}
+
+	if r.Description == nil {
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "%s.\n", sentenceCase(r.Description.Friendly()))
+
+	if len(r.Description.Members) > 0 {
+		sb.WriteString("\n## Member types\n\n| Type | Count |\n| --- | ---: |\n")
+		for _, k := range sortedKeys(r.Description.Members) {
+			fmt.Fprintf(&sb, "| %s | %d |\n", k, r.Description.Members[k])
+		}
+	}
+
+	if len(r.Keys) > 0 {
+		sb.WriteString("\n## Keys\n\n| Path | Type |\n| --- | --- |\n")
+		for _, kt := range r.Keys {
+			fmt.Fprintf(&sb, "| %s | %s |\n", markdownCode(kt.Key), kt.Type)
+		}
+	}
+
+	return sb.String()
+}
+
+// Builds the structure tree beneath raw in document order
+func reportTree(name string, raw json.RawMessage) (*ReportNode, error) {
+	typ, _ := TypeOf(raw)
+	node := &ReportNode{Name: name, Type: *typ}
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return node, err
+		}
+		for _, m := range members {
+			child, err := reportTree(SafeKey(m.Key), m.Value)
+			if err != nil {
+				return node, err
+			}
+			node.Children = append(node.Children, child)
+		}
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return node, err
+		}
+		for i := range arr {
+			child, err := reportTree(fmt.Sprintf("[%d]", i), arr[i])
+			if err != nil {
+				return node, err
+			}
+			node.Children = append(node.Children, child)
+		}
+	}
+
+	return node, nil
+}
+
+// Capitalizes the first letter of a Friendly description
+func sentenceCase(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Formats text as an inline code span that is safe inside a table cell
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(SafeKey(s), "|", `\|`)
+
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+
+	return "`" + s + "`"
+}
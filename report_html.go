@@ -0,0 +1,74 @@
+package jsondescriber
+
+import (
+	"html/template"
+	"strings"
+)
+
+// A standalone page: summary, collapsible structure tree, and tables of member types and keys
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .Title}}{{.Title}}{{else}}JSON structure{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: left; }
+td.count { text-align: right; }
+code, summary, li { font-family: ui-monospace, monospace; }
+ul.tree, ul.tree ul { list-style: none; padding-left: 1.25em; }
+.type { color: #777; }
+</style>
+</head>
+<body>
+{{if .Title}}<h1>{{.Title}}</h1>
+{{end}}<p>{{.Summary}}.</p>
+{{if .Tree}}<h2>Structure</h2>
+<ul class="tree">{{template "node" .Tree}}</ul>
+{{end}}{{if .Members}}<h2>Member types</h2>
+<table>
+<tr><th>Type</th><th>Count</th></tr>
+{{range .Members}}<tr><td>{{.Type}}</td><td class="count">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Keys}}<h2>Keys</h2>
+<table>
+<tr><th>Path</th><th>Type</th></tr>
+{{range .Keys}}<tr><td><code>{{.Key}}</code></td><td>{{.Type}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+{{define "node"}}<li>{{if .Children}}<details{{if not .Name}} open{{end}}><summary>{{if .Name}}{{.Name}}: {{end}}<span class="type">{{.Type}}</span></summary>
+<ul>{{range .Children}}{{template "node" .}}{{end}}</ul>
+</details>{{else}}{{if .Name}}{{.Name}}: {{end}}<span class="type">{{.Type}}</span>{{end}}</li>
+{{end}}`))
+
+// The view of a Report given to htmlReport
+type htmlReportData struct {
+	Title   string
+	Summary string
+	Tree    *ReportNode
+	Members []TypeCount
+	Keys    []KeyType
+}
+
+// Renders the report as a standalone HTML page with a collapsible structure tree
+func (r *Report) HTML() string {
+	var (
+		sb   strings.Builder
+		data = htmlReportData{
+			Title: r.Title,
+			Tree:  r.Tree,
+			Keys:  r.Keys,
+		}
+	)
+
+	if r.Description != nil {
+		data.Summary = sentenceCase(r.Description.Friendly())
+
+		data.Members = sortCounts(r.Description.Members, ByTypeName)
+	}
+
+	htmlReport.Execute(&sb, data)
+	return sb.String()
+}
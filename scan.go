@@ -0,0 +1,363 @@
+package jsondescriber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Validates JSON in a single pass over its bytes, reporting the types of top-level members as it goes
+type scanner struct {
+	data  []byte
+	pos   int
+	depth int
+	cfg   *config
+	// When set, checked every cancelCheckInterval members, ending the scan with its error once it is done
+	ctx     context.Context
+	members int
+	// When set, receives every number with its path; array indices in the path are wildcards
+	onNumber func(path string, literal []byte)
+	// When set, malformed members of the top-level container are passed over and reported here by position, key, and offset, instead of failing the scan
+	onSkip func(index int, key []byte, offset int, err error)
+}
+
+// Receives each member of the top-level container; key is the raw quoted key, or nil for array elements, and raw the member's value as written
+//
+// Returning false stops the scan there, leaving the rest of the document unread.
+type memberFunc func(key []byte, typ JsonType, raw []byte) bool
+
+// Ends a scan early at the request of a memberFunc
+var errStopScan = errors.New("scan stopped")
+
+// Reports invalid input at the current position
+func (s *scanner) fail() error {
+	if s.pos >= len(s.data) {
+		return fmt.Errorf("%w: unexpected end of input", ErrInvalidJson)
+	}
+
+	return fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidJson, s.data[s.pos], s.pos)
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// Scans a whole document, which must hold exactly one value
+func (s *scanner) document(visit memberFunc) (JsonType, error) {
+	if s.cfg.maxBytes > 0 && len(s.data) > s.cfg.maxBytes {
+		return Undefined, &LimitError{Limit: "bytes", Max: s.cfg.maxBytes, Offset: int64(s.cfg.maxBytes)}
+	}
+
+	typ, err := s.value("", visit)
+
+	if err == errStopScan {
+		return typ, nil
+	}
+
+	if err != nil {
+		return Undefined, err
+	}
+
+	if s.skipSpace(); s.pos < len(s.data) {
+		return Undefined, &TrailingDataError{Offset: int64(s.pos)}
+	}
+
+	return typ, nil
+}
+
+// Scans one value found at path, passing visit on to it only if it is a container
+func (s *scanner) value(path string, visit memberFunc) (JsonType, error) {
+	s.skipSpace()
+
+	if s.pos >= len(s.data) {
+		return Undefined, s.fail()
+	}
+
+	switch c := s.data[s.pos]; {
+	case c == '{':
+		return Object, s.container('}', path, visit)
+	case c == '[':
+		return Array, s.container(']', path, visit)
+	case c == '"':
+		return String, s.str()
+	case c == 't':
+		return True, s.literal("true")
+	case c == 'f':
+		return False, s.literal("false")
+	case c == 'n':
+		return Null, s.literal("null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := s.pos
+		err := s.number()
+		if err == nil && s.onNumber != nil {
+			s.onNumber(path, s.data[start:s.pos])
+		}
+		return Number, err
+	}
+
+	return Undefined, s.fail()
+}
+
+// Scans an object or array whose opening bracket is at the current position
+func (s *scanner) container(closer byte, path string, visit memberFunc) error {
+	if s.depth++; s.depth > stdlibMaxDepth {
+		return ErrTooDeep
+	}
+
+	if s.cfg.maxDepth > 0 && s.depth > s.cfg.maxDepth {
+		return &LimitError{Limit: "depth", Max: s.cfg.maxDepth, Offset: int64(s.pos)}
+	}
+
+	s.pos++
+	s.skipSpace()
+
+	if s.pos < len(s.data) && s.data[s.pos] == closer {
+		s.pos++
+		s.depth--
+		return nil
+	}
+
+	for members := 1; ; members++ {
+		if s.members++; s.ctx != nil && s.members%cancelCheckInterval == 1 {
+			if err := s.ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		if s.cfg.maxMembers > 0 && members > s.cfg.maxMembers {
+			return &LimitError{Limit: "members", Max: s.cfg.maxMembers, Offset: int64(s.pos)}
+		}
+
+		var (
+			start = s.pos
+			depth = s.depth
+		)
+
+		key, typ, raw, err := s.member(closer, path)
+
+		// A lenient scan must know the member ends cleanly before counting it
+		if err == nil && s.onSkip != nil && visit != nil {
+			if s.skipSpace(); s.pos >= len(s.data) || s.data[s.pos] != ',' && s.data[s.pos] != closer {
+				err = s.fail()
+			}
+		}
+
+		switch {
+		case err == nil:
+			if visit != nil && !visit(key, typ, raw) {
+				return errStopScan
+			}
+		case s.onSkip != nil && visit != nil && errors.Is(err, ErrInvalidJson) && s.skipMember(closer, start):
+			s.depth = depth
+			s.onSkip(members-1, key, start, err)
+		default:
+			return err
+		}
+
+		if s.skipSpace(); s.pos >= len(s.data) {
+			return s.fail()
+		}
+
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+			s.skipSpace()
+		case closer:
+			s.pos++
+			s.depth--
+			return nil
+		default:
+			return s.fail()
+		}
+	}
+}
+
+// Scans one member of a container: its key and colon, for an object, then its value; key is nil for arrays or if the key itself was malformed
+func (s *scanner) member(closer byte, path string) (key []byte, typ JsonType, raw []byte, err error) {
+	var child string
+
+	if closer == '}' {
+		start := s.pos
+
+		if s.pos >= len(s.data) || s.data[s.pos] != '"' {
+			return nil, Undefined, nil, s.fail()
+		}
+
+		if err := s.str(); err != nil {
+			return nil, Undefined, nil, err
+		}
+
+		key = s.data[start:s.pos]
+
+		if s.skipSpace(); s.pos >= len(s.data) || s.data[s.pos] != ':' {
+			return key, Undefined, nil, s.fail()
+		}
+
+		s.pos++
+	}
+
+	// Paths are only built when something will read them
+	if s.onNumber != nil {
+		if key != nil {
+			child = joinKey(s.cfg.pathStyle, path, unquote(key))
+		} else {
+			child = joinWildcard(s.cfg.pathStyle, path)
+		}
+	}
+
+	s.skipSpace()
+	start := s.pos
+
+	typ, err = s.value(child, nil)
+
+	return key, typ, s.data[start:s.pos], err
+}
+
+// Moves from the start of a malformed member to the comma or closer that ends it, passing over strings and balanced brackets; false if the container never closes
+func (s *scanner) skipMember(closer byte, start int) bool {
+	var (
+		depth    int
+		inString bool
+	)
+
+	for s.pos = start; s.pos < len(s.data); s.pos++ {
+		c := s.data[s.pos]
+
+		if inString {
+			if c == '\\' {
+				s.pos++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 && c == closer {
+				return true
+			}
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Scans a string literal whose opening quote is at the current position
+func (s *scanner) str() error {
+	for s.pos++; s.pos < len(s.data); s.pos++ {
+		switch c := s.data[s.pos]; {
+		case c == '"':
+			s.pos++
+			return nil
+		case c < 0x20:
+			return s.fail()
+		case c == '\\':
+			if s.pos++; s.pos >= len(s.data) {
+				return s.fail()
+			}
+
+			switch s.data[s.pos] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				for i := 0; i < 4; i++ {
+					if s.pos++; s.pos >= len(s.data) || !isHex(s.data[s.pos]) {
+						return s.fail()
+					}
+				}
+			default:
+				return s.fail()
+			}
+		}
+	}
+
+	return s.fail()
+}
+
+// Scans a number: an optional minus, an integer without leading zeros, then optional fraction and exponent
+func (s *scanner) number() error {
+	if s.data[s.pos] == '-' {
+		s.pos++
+	}
+
+	if s.pos < len(s.data) && s.data[s.pos] == '0' {
+		s.pos++
+	} else if !s.digits() {
+		return s.fail()
+	}
+
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		if s.pos++; !s.digits() {
+			return s.fail()
+		}
+	}
+
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		if s.pos++; s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+			s.pos++
+		}
+		if !s.digits() {
+			return s.fail()
+		}
+	}
+
+	return nil
+}
+
+// Consumes a run of decimal digits, reporting whether there was at least one
+func (s *scanner) digits() bool {
+	start := s.pos
+
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+
+	return s.pos > start
+}
+
+func (s *scanner) literal(lit string) error {
+	for i := 0; i < len(lit); i++ {
+		if s.pos >= len(s.data) || s.data[s.pos] != lit[i] {
+			return s.fail()
+		}
+		s.pos++
+	}
+
+	return nil
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// Decodes a raw quoted string as scanned, taking the fast path when it has no escapes
+func unquote(raw []byte) string {
+	for _, c := range raw {
+		if c == '\\' {
+			var key string
+			json.Unmarshal(raw, &key)
+			return key
+		}
+	}
+
+	return string(raw[1 : len(raw)-1])
+}
@@ -0,0 +1,390 @@
+package jsondescriber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The inferred structure of every value seen at one location across a set of documents
+type Shape struct {
+	// How many values were seen here
+	Count uint
+	// How many of those values were of each type, keyed by type name
+	Types map[string]uint
+	// How many of the numbers were written without a fraction or exponent
+	Integers uint
+	// The first string, number, or boolean seen here, as JSON; nil if there was none
+	Example json.RawMessage
+	// How many of the strings were of each recognized format, keyed by format name as in JsonDescription.Formats; nil if none were
+	Formats map[string]uint
+	// How many of the strings contained whitespace, as prose does
+	Spaced uint
+	// The length in bytes of the longest string seen here
+	MaxLength uint
+	// Member keys of the objects seen here, in the order first seen
+	Keys []string
+	// The shape of each member of the objects seen here
+	Fields map[string]*Shape
+	// The shape of every element of the arrays seen here, merged; nil if no array had elements
+	Items *Shape
+
+	// The object being added when this member was last counted, so a repeated key counts once
+	stamp uint64
+}
+
+// Constructor for Shape that initializes its maps
+func NewShape() *Shape {
+	return &Shape{
+		Types:  make(map[string]uint),
+		Keys:   make([]string, 0),
+		Fields: make(map[string]*Shape),
+	}
+}
+
+// The share of objects at this location that had key, from 0 to 1
+func (s *Shape) Presence(key string) float64 {
+	objects := s.Types["object"]
+	field, ok := s.Fields[key]
+
+	if !ok || objects == 0 {
+		return 0
+	}
+
+	return float64(field.Count) / float64(objects)
+}
+
+// Reports whether every object at this location had key
+func (s *Shape) Required(key string) bool {
+	field, ok := s.Fields[key]
+	return ok && field.Count == s.Types["object"]
+}
+
+// Reports whether any value seen here was null
+func (s *Shape) Nullable() bool {
+	return s.Types["null"] > 0
+}
+
+// Lists the keys every object at this location had, in first-seen order
+func (s *Shape) RequiredKeys() []string {
+	return s.filterKeys(func(k string) bool { return s.Required(k) })
+}
+
+// Lists the keys only some objects at this location had, in first-seen order
+func (s *Shape) OptionalKeys() []string {
+	return s.filterKeys(func(k string) bool { return !s.Required(k) })
+}
+
+// Lists the keys that were null at least once, in first-seen order
+func (s *Shape) NullableKeys() []string {
+	return s.filterKeys(func(k string) bool { return s.Fields[k].Nullable() })
+}
+
+func (s *Shape) filterKeys(keep func(k string) bool) []string {
+	keys := make([]string, 0)
+
+	for _, k := range s.Keys {
+		if keep(k) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// Counts the values of o into s as though they had been seen here, member by member
+func (s *Shape) merge(o *Shape) {
+	s.Count += o.Count
+	s.Integers += o.Integers
+
+	if s.Example == nil {
+		s.Example = o.Example
+	}
+
+	for f, n := range o.Formats {
+		if s.Formats == nil {
+			s.Formats = make(map[string]uint)
+		}
+		s.Formats[f] += n
+	}
+
+	s.Spaced += o.Spaced
+	if o.MaxLength > s.MaxLength {
+		s.MaxLength = o.MaxLength
+	}
+
+	for t, n := range o.Types {
+		s.Types[t] += n
+	}
+
+	for _, k := range o.Keys {
+		s.field(k).merge(o.Fields[k])
+	}
+
+	if o.Items != nil {
+		if s.Items == nil {
+			s.Items = NewShape()
+		}
+		s.Items.merge(o.Items)
+	}
+}
+
+// Returns the shape of member key, creating it on first sight
+func (s *Shape) field(key string) *Shape {
+	f, ok := s.Fields[key]
+
+	if !ok {
+		f = NewShape()
+		s.Fields[key] = f
+		s.Keys = append(s.Keys, key)
+	}
+
+	return f
+}
+
+// Merges documents one at a time into a single Shape
+//
+// An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	root    *Shape
+	stamp   uint64
+	errors  uint
+	metrics Metrics
+}
+
+// Constructor for Aggregator
+//
+// Honors WithMetrics.
+func NewAggregator(opts ...Option) *Aggregator {
+	return &Aggregator{root: NewShape(), metrics: newConfig(opts).metrics}
+}
+
+// Merges one JSON document into the shape, leaving it untouched if data is not valid JSON
+//
+// A key repeated within one object counts once, by its first value.
+func (a *Aggregator) Add(data []byte) error {
+	if !json.Valid(data) {
+		a.errors++
+		err := fmt.Errorf("document %d: %w", a.root.Count+a.errors, ErrInvalidJson)
+
+		if a.metrics != nil {
+			a.metrics.DocumentRejected(err)
+		}
+
+		return err
+	}
+
+	w := newTokenWalker(context.Background(), bytes.NewReader(data), newConfig(nil))
+	tok, err := w.token()
+
+	if err == nil {
+		err = a.add(w, tok, a.root)
+	}
+
+	if err == nil && a.metrics != nil {
+		a.metrics.DocumentAdded(len(data))
+	}
+
+	return err
+}
+
+// The shape of every document added so far; it keeps changing as more are added
+func (a *Aggregator) Shape() *Shape {
+	return a.root
+}
+
+// How many documents have been merged into the shape
+func (a *Aggregator) Documents() uint {
+	return a.root.Count
+}
+
+// How many documents Add has rejected
+func (a *Aggregator) Errors() uint {
+	return a.errors
+}
+
+// Records the value that tok begins at the location s describes
+func (a *Aggregator) add(w *tokenWalker, tok json.Token, s *Shape) error {
+	s.Count++
+	s.Types[tokenType(tok).String()] += 1
+
+	if a.metrics != nil {
+		a.metrics.ValueSeen(tokenType(tok))
+	}
+
+	if num, ok := tok.(json.Number); ok && !strings.ContainsAny(string(num), ".eE") {
+		s.Integers++
+	}
+
+	if _, ok := tok.(json.Delim); !ok && tok != nil && s.Example == nil {
+		s.Example, _ = json.Marshal(tok)
+	}
+
+	if str, ok := tok.(string); ok {
+		s.addString(str)
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		a.stamp++
+		stamp := a.stamp
+
+		for w.dec.More() {
+			key, err := w.token()
+			if err != nil {
+				return err
+			}
+
+			val, err := w.token()
+			if err != nil {
+				return err
+			}
+
+			f := s.field(key.(string))
+
+			if f.stamp == stamp {
+				if err = w.skip(val); err != nil {
+					return err
+				}
+				continue
+			}
+
+			f.stamp = stamp
+
+			if err = a.add(w, val, f); err != nil {
+				return err
+			}
+		}
+
+		_, err := w.token()
+		return err
+
+	case json.Delim('['):
+		for w.dec.More() {
+			val, err := w.token()
+			if err != nil {
+				return err
+			}
+
+			if s.Items == nil {
+				s.Items = NewShape()
+			}
+
+			if err = a.add(w, val, s.Items); err != nil {
+				return err
+			}
+		}
+
+		_, err := w.token()
+		return err
+	}
+
+	return nil
+}
+
+// Records the format, whitespace, and length of a string seen here
+func (s *Shape) addString(str string) {
+	if f := DetectFormat(str); f != NoFormat {
+		if s.Formats == nil {
+			s.Formats = make(map[string]uint)
+		}
+		s.Formats[f.String()]++
+	}
+
+	if strings.ContainsAny(str, " \t\r\n") {
+		s.Spaced++
+	}
+
+	if n := uint(len(str)); n > s.MaxLength {
+		s.MaxLength = n
+	}
+}
+
+// Merges each line of r as a document, as from an NDJSON log, so that its shape can be built without holding the whole input
+//
+// Blank lines are skipped, and lines that are not valid JSON are counted by Errors and otherwise ignored; only a failure to read r is returned. A final line need not end in a newline. Honors WithDecompression.
+func (a *Aggregator) AddReader(r io.Reader, opts ...Option) error {
+	return a.AddReaderContext(context.Background(), r, opts...)
+}
+
+// Like AddReader, but gives up with ctx.Err() once ctx is done, checking between lines
+func (a *Aggregator) AddReaderContext(ctx context.Context, r io.Reader, opts ...Option) error {
+	r, err := newConfig(opts).decompress(r)
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := br.ReadBytes('\n')
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			a.Add(line)
+		}
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// Observations at one location of a Shape, as listed by Histograms
+type KeyHistogram struct {
+	// The location, with array indices as wildcards; "" for the documents themselves
+	Path string
+	// How many values were seen here
+	Count uint
+	// The share of the enclosing objects that had this member, from 0 to 1; 1 for documents and array elements
+	Presence float64
+	// How many of the values were of each type, keyed by type name
+	Types map[string]uint
+}
+
+// Flattens the shape into one histogram per location, parents before children and members in the order first seen
+//
+// Honors WithPathStyle.
+func (s *Shape) Histograms(opts ...Option) []KeyHistogram {
+	var (
+		cfg   = newConfig(opts)
+		hists = make([]KeyHistogram, 0)
+	)
+
+	s.histograms("", 1, cfg.pathStyle, &hists)
+	return hists
+}
+
+func (s *Shape) histograms(path string, presence float64, style PathStyle, hists *[]KeyHistogram) {
+	*hists = append(*hists, KeyHistogram{Path: path, Count: s.Count, Presence: presence, Types: s.Types})
+
+	for _, k := range s.Keys {
+		s.Fields[k].histograms(joinKey(style, path, k), s.Presence(k), style, hists)
+	}
+
+	if s.Items != nil {
+		s.Items.histograms(joinWildcard(style, path), 1, style, hists)
+	}
+}
+
+// Infers the shape shared by several documents
+func InferShape(docs ...[]byte) (*Shape, error) {
+	agg := NewAggregator()
+
+	for _, doc := range docs {
+		if err := agg.Add(doc); err != nil {
+			return agg.Shape(), err
+		}
+	}
+
+	return agg.Shape(), nil
+}
@@ -0,0 +1,115 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// One row of a side-by-side rendering
+type sideRow struct {
+	Key    string
+	Old    string
+	New    string
+	Marker string
+	Class  string
+}
+
+// Lines up the changes by key, marking each as sdiff does: ">" added, "<" deleted, "|" modified, replaced, changed type, respelled, or renamed, with a respelled or renamed key shown both ways
+func (d *DetailedDiff) sideRows() []sideRow {
+	rows := make([]sideRow, 0, d.Total())
+
+	add := func(changes []ValueChange, marker, class string) {
+		for _, c := range changes {
+			rows = append(rows, sideRow{Key: SafeKey(c.Key), Old: c.Old, New: c.New, Marker: marker, Class: class})
+		}
+	}
+
+	add(d.Added, ">", "added")
+	add(d.Deleted, "<", "deleted")
+	add(d.Modified, "|", "modified")
+	add(d.Replaced, "|", "replaced")
+	add(d.TypeChanged, "|", "typechanged")
+
+	moved := func(changes []ValueChange, class string) {
+		for _, c := range changes {
+			rows = append(rows, sideRow{Key: SafeKey(c.OldKey) + " → " + SafeKey(c.Key), Old: c.Old, New: c.New, Marker: "|", Class: class})
+		}
+	}
+
+	moved(d.Respelled, "respelled")
+	moved(d.Renamed, "renamed")
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Key < rows[j].Key
+	})
+
+	return rows
+}
+
+// Renders the changes in two columns, old values on the left and new on the right, aligned by key with a change marker between
+//
+// Values too long for their column are cut with an ellipsis. Honors WithWidth.
+func (d *DetailedDiff) SideBySide(opts ...Option) string {
+	var (
+		cfg  = newConfig(opts)
+		rows = d.sideRows()
+		sb   strings.Builder
+		keyW = 0
+	)
+
+	for _, r := range rows {
+		if n := utf8.RuneCountInString(r.Key); n > keyW {
+			keyW = n
+		}
+	}
+
+	// The key column and " " + marker + " " between the value columns
+	valW := (cfg.width - keyW - 5) / 2
+	if valW < 8 {
+		valW = 8
+	}
+
+	for _, r := range rows {
+		line := fmt.Sprintf("%s  %s %s %s", padRight(r.Key, keyW), padRight(fitColumn(r.Old, valW), valW), r.Marker, fitColumn(r.New, valW))
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	return sb.String()
+}
+
+// Cuts s to at most width characters, marking a cut with an ellipsis
+func fitColumn(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// Pads s with spaces to width characters
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
+
+// A table for embedding in a page; rows carry the class of their change for styling
+var htmlSideBySide = template.Must(template.New("sidebyside").Parse(`<table class="jsondiff">
+<tr><th>Key</th><th>Old</th><th></th><th>New</th></tr>
+{{range .}}<tr class="{{.Class}}"><td><code>{{.Key}}</code></td><td><code>{{.Old}}</code></td><td>{{.Marker}}</td><td><code>{{.New}}</code></td></tr>
+{{end}}</table>
+`))
+
+// Renders the changes as an HTML table with key, old, marker, and new columns; each row's class is added, deleted, modified, replaced, typechanged, respelled, or renamed
+func (d *DetailedDiff) SideBySideHTML() string {
+	var sb strings.Builder
+
+	htmlSideBySide.Execute(&sb, d.sideRows())
+	return sb.String()
+}
@@ -0,0 +1,143 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Scores how alike two documents are in structure, from 0 (nothing shared) to 1 (the same paths with the same types)
+//
+// The score is the Jaccard index of the documents' path:type pairs, with array indices as wildcards so that lengths do not matter and true and false counted as one boolean type. Values are ignored.
+func Similarity(a, b []byte) (float64, error) {
+	var sets [2]map[string]bool
+
+	for i, data := range [][]byte{a, b} {
+		set, err := pathSet(data)
+		if err != nil {
+			return 0, err
+		}
+		sets[i] = set
+	}
+
+	return jaccard(sets[0], sets[1]), nil
+}
+
+// Collects the path:type pairs of a document for Similarity
+func pathSet(data []byte) (map[string]bool, error) {
+	data = bytes.TrimSpace(data)
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool)
+	structurePaths(data, "", set)
+	return set, nil
+}
+
+// The size of the intersection of two sets over the size of their union
+func jaccard(a, b map[string]bool) float64 {
+	var shared int
+
+	for p := range a {
+		if b[p] {
+			shared++
+		}
+	}
+
+	if union := len(a) + len(b) - shared; union > 0 {
+		return float64(shared) / float64(union)
+	}
+
+	return 1
+}
+
+// Records the path:type pair of a valid value and everything within it, with array indices as wildcards
+func structurePaths(raw json.RawMessage, path string, set map[string]bool) {
+	typ, _ := JsonTypeOf(raw)
+	name := typ.String()
+
+	switch typ {
+	case Object:
+		obj := make(RawObject)
+		json.Unmarshal(raw, &obj)
+		for k := range obj {
+			structurePaths(obj[k], joinKey(PointerPath, path, k), set)
+		}
+
+	case Array:
+		arr := make(RawArray, 0)
+		json.Unmarshal(raw, &arr)
+		for i := range arr {
+			structurePaths(arr[i], joinWildcard(PointerPath, path), set)
+		}
+
+	case True, False:
+		name = "boolean"
+	}
+
+	set[path+":"+name] = true
+}
+
+// Scores how alike two values of the same type are, for WithReplaceThreshold: scalars by edit distance over their canonical text, containers by the Jaccard index of their leaves' path=value pairs
+func (c *config) valueSimilarity(a, b json.RawMessage) float64 {
+	ta, _ := JsonTypeOf(a)
+	tb, _ := JsonTypeOf(b)
+
+	if ta != tb {
+		return 0
+	}
+
+	if !ta.IsContainer() {
+		if ta == String {
+			return editSimilarity(unquote(a), unquote(b))
+		}
+		return editSimilarity(c.canonical(a), c.canonical(b))
+	}
+
+	var sets [2]map[string]bool
+
+	for i, raw := range []json.RawMessage{a, b} {
+		flat := make(map[string]json.RawMessage)
+		flatten(bytes.TrimSpace(raw), "", PointerPath, flat)
+
+		sets[i] = make(map[string]bool, len(flat))
+		for p, leaf := range flat {
+			sets[i][p+"="+c.canonical(leaf)] = true
+		}
+	}
+
+	return jaccard(sets[0], sets[1])
+}
+
+// One less the Levenshtein distance between two strings, in characters, over the length of the longer
+func editSimilarity(a, b string) float64 {
+	var (
+		ra   = []rune(a)
+		rb   = []rune(b)
+		prev = make([]int, len(rb)+1)
+		cur  = make([]int, len(rb)+1)
+	)
+
+	if a == b {
+		return 1
+	}
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
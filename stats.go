@@ -0,0 +1,88 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Summarizes the numbers found at one path of a document; see WithNumericStats
+type NumberStats struct {
+	Count uint
+	// How many of the numbers were written without a fraction or exponent
+	Integers uint
+	Min      float64
+	Max      float64
+	Mean     float64
+	// The smallest and largest numbers as written, under WithExactNumbers; empty otherwise
+	MinText string
+	MaxText string
+}
+
+// The share of numbers that were integers, from 0 to 1
+func (s *NumberStats) IntegerRatio() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return float64(s.Integers) / float64(s.Count)
+}
+
+// Folds one number literal into the statistics, ordering it exactly if asked
+func (s *NumberStats) add(literal []byte, exact bool) {
+	// The scanner has already checked the syntax, so only overflow to ±Inf can fail, and that still orders correctly
+	v, _ := strconv.ParseFloat(string(literal), 64)
+
+	if exact {
+		s.addExact(string(literal), v)
+	} else {
+		if s.Count == 0 || v < s.Min {
+			s.Min = v
+		}
+
+		if s.Count == 0 || v > s.Max {
+			s.Max = v
+		}
+	}
+
+	s.Count++
+	s.Mean += (v - s.Mean) / float64(s.Count)
+
+	if bytes.IndexAny(literal, ".eE") < 0 {
+		s.Integers++
+	}
+}
+
+// Orders one number against the smallest and largest by exact value, keeping their literals; one whose exponent overflows orders by v
+func (s *NumberStats) addExact(literal string, v float64) {
+	if s.Count == 0 {
+		s.Min, s.Max, s.MinText, s.MaxText = v, v, literal, literal
+		return
+	}
+
+	if compareLiterals(literal, v, s.MinText, s.Min) < 0 {
+		s.Min, s.MinText = v, literal
+	}
+
+	if compareLiterals(literal, v, s.MaxText, s.Max) > 0 {
+		s.Max, s.MaxText = v, literal
+	}
+}
+
+// Orders two number literals exactly, or by their float64 values when either cannot be taken apart
+func compareLiterals(a string, av float64, b string, bv float64) int {
+	da, okA := parseDecimal(a)
+	db, okB := parseDecimal(b)
+
+	if okA && okB {
+		return da.cmp(db)
+	}
+
+	switch {
+	case av < bv:
+		return -1
+	case av > bv:
+		return 1
+	}
+
+	return 0
+}
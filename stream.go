@@ -0,0 +1,263 @@
+package jsondescriber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// How many tokens, or members under DescribeContext, are read between checks for cancellation
+const cancelCheckInterval = 1024
+
+// Reads a JSON document token by token, checking a context and the size guards as it goes
+type tokenWalker struct {
+	ctx  context.Context
+	cfg  *config
+	r    io.Reader
+	dec  *json.Decoder
+	read int
+	// Open containers, innermost last
+	open []openContainer
+}
+
+// Tracks the members of a container the walker is inside
+type openContainer struct {
+	object    bool
+	members   int
+	expectKey bool
+}
+
+func newTokenWalker(ctx context.Context, r io.Reader, cfg *config) *tokenWalker {
+	if cfg.maxBytes > 0 {
+		r = &limitReader{r: r, max: int64(cfg.maxBytes)}
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &tokenWalker{ctx: ctx, cfg: cfg, r: r, dec: dec}
+}
+
+// Returns the next token, failing once the context is done or the input turns out not to be JSON
+func (w *tokenWalker) token() (json.Token, error) {
+	if w.read++; w.read%cancelCheckInterval == 1 {
+		if err := w.ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	tok, err := w.dec.Token()
+
+	var syntax *json.SyntaxError
+
+	switch {
+	case err == io.EOF, err == io.ErrUnexpectedEOF:
+		return nil, fmt.Errorf("%w: unexpected end of input", ErrInvalidJson)
+	case errors.As(err, &syntax):
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJson, err)
+	case err != nil:
+		return nil, err
+	}
+
+	return tok, w.track(tok)
+}
+
+// Applies WithMaxDepth and WithMaxMembers to the token just read
+func (w *tokenWalker) track(tok json.Token) error {
+	if tok == json.Delim('}') || tok == json.Delim(']') {
+		w.open = w.open[:len(w.open)-1]
+		return nil
+	}
+
+	if top := len(w.open) - 1; top >= 0 {
+		c := &w.open[top]
+
+		if !c.object || c.expectKey {
+			if c.members++; w.cfg.maxMembers > 0 && c.members > w.cfg.maxMembers {
+				return &LimitError{Limit: "members", Max: w.cfg.maxMembers, Offset: w.dec.InputOffset()}
+			}
+		}
+
+		if c.object {
+			c.expectKey = !c.expectKey
+		}
+	}
+
+	if tok == json.Delim('{') || tok == json.Delim('[') {
+		w.open = append(w.open, openContainer{object: tok == json.Delim('{'), expectKey: true})
+
+		if w.cfg.maxDepth > 0 && len(w.open) > w.cfg.maxDepth {
+			return &LimitError{Limit: "depth", Max: w.cfg.maxDepth, Offset: w.dec.InputOffset() - 1}
+		}
+	}
+
+	return nil
+}
+
+// Consumes the rest of the value that tok begins
+func (w *tokenWalker) skip(tok json.Token) error {
+	if tok != json.Delim('{') && tok != json.Delim('[') {
+		return nil
+	}
+
+	for depth := 1; depth > 0; {
+		tok, err := w.token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// Fails with a *TrailingDataError if anything but whitespace follows the value just read
+func (w *tokenWalker) finish() error {
+	var (
+		rest = bufio.NewReader(io.MultiReader(w.dec.Buffered(), w.r))
+		off  = w.dec.InputOffset()
+	)
+
+	for {
+		c, err := rest.ReadByte()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if bytes.IndexByte([]byte(" \t\r\n"), c) < 0 {
+			return &TrailingDataError{Offset: off}
+		}
+
+		off++
+	}
+}
+
+// The type of the value a token begins
+func tokenType(tok json.Token) JsonType {
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return Object
+		}
+		return Array
+	case string:
+		return String
+	case json.Number:
+		return Number
+	case bool:
+		if v {
+			return True
+		}
+		return False
+	case nil:
+		return Null
+	}
+
+	return Undefined
+}
+
+// Generates a populated JsonDescription from JSON read from r, without holding the whole document in memory
+//
+// Honors WithMaxDepth, WithMaxBytes, WithMaxMembers, and WithDecompression.
+func DescribeReader(r io.Reader, opts ...Option) (*JsonDescription, error) {
+	return DescribeReaderContext(context.Background(), r, opts...)
+}
+
+// Like Describe, but gives up with ctx.Err() once ctx is done
+//
+// Honors the options Describe does, except that a ctx that can be done turns off WithParallelism.
+func DescribeContext(ctx context.Context, data []byte, opts ...Option) (*JsonDescription, error) {
+	return describeContext(ctx, data, opts)
+}
+
+// Like DescribeReader, but gives up with ctx.Err() once ctx is done
+//
+// A key repeated within the top-level object is counted once, by its last value, as Describe would. Honors WithMaxDepth, WithMaxBytes, WithMaxMembers, and WithDecompression.
+func DescribeReaderContext(ctx context.Context, r io.Reader, opts ...Option) (*JsonDescription, error) {
+	var (
+		descr = NewJsonDescription()
+		cfg   = newConfig(opts)
+	)
+
+	r, err := cfg.decompress(r)
+	if err != nil {
+		return descr, err
+	}
+
+	w := newTokenWalker(ctx, r, cfg)
+	tok, err := w.token()
+
+	if err != nil {
+		return descr, err
+	}
+
+	descr.Element = tokenType(tok).String()
+
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]JsonType)
+
+		for w.dec.More() {
+			key, err := w.token()
+			if err != nil {
+				return descr, err
+			}
+
+			val, err := w.token()
+			if err != nil {
+				return descr, err
+			}
+
+			if prev, ok := seen[key.(string)]; ok {
+				if descr.Members[prev.String()]--; descr.Members[prev.String()] == 0 {
+					delete(descr.Members, prev.String())
+				}
+			}
+
+			seen[key.(string)] = tokenType(val)
+			descr.Members[tokenType(val).String()] += 1
+
+			if err = w.skip(val); err != nil {
+				return descr, err
+			}
+		}
+
+		_, err = w.token()
+
+	case json.Delim('['):
+		for w.dec.More() {
+			val, err := w.token()
+			if err != nil {
+				return descr, err
+			}
+
+			descr.Members[tokenType(val).String()] += 1
+
+			if err = w.skip(val); err != nil {
+				return descr, err
+			}
+		}
+
+		_, err = w.token()
+	}
+
+	if err != nil {
+		return descr, err
+	}
+
+	return descr, w.finish()
+}
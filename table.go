@@ -0,0 +1,110 @@
+package jsondescriber
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Escapes the characters that would break a table row or its alignment
+var cellEscaper = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// Prints the array's objects to w as an aligned text table, one row each, headed by every key any of them had, in the order keys were first seen
+//
+// Cells hold values as ToCSV writes them, except that nulls read null and missing keys are left blank, and tabs and newlines are escaped. Every element must be an object. Honors WithColumns, WithTruncate, and WithTypeAnnotations.
+func (a *RawArray) Table(w io.Writer, opts ...Option) error {
+	var (
+		cfg     = newConfig(opts)
+		columns = cfg.columns
+		known   = make(map[string]bool)
+		rows    = make([]RawObject, len(*a))
+		tw      = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	)
+
+	for i, elem := range *a {
+		members, err := orderedMembers(elem)
+		if err != nil {
+			return fmt.Errorf("element %d is not an object", i)
+		}
+
+		rows[i] = make(RawObject, len(members))
+
+		for _, m := range members {
+			if _, ok := rows[i][m.Key]; ok {
+				continue
+			}
+			rows[i][m.Key] = m.Value
+			if cfg.columns == nil && !known[m.Key] {
+				known[m.Key] = true
+				columns = append(columns, m.Key)
+			}
+		}
+	}
+
+	header := make([]string, len(columns))
+
+	for i, k := range columns {
+		header[i] = cellEscaper.Replace(k)
+		if cfg.typeAnnotations {
+			header[i] += " (" + columnTypes(rows, k) + ")"
+		}
+	}
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+
+		for i, k := range columns {
+			raw, ok := row[k]
+			if !ok {
+				continue
+			}
+
+			text := "null"
+			if typ, _ := JsonTypeOf(raw); typ != Null {
+				text = csvCell(raw)
+			}
+
+			cells[i], _ = cfg.cut(cellEscaper.Replace(text))
+		}
+
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// Lists the types the rows held under key, joined with "|" in the order of JsonType, with true and false as boolean
+func columnTypes(rows []RawObject, key string) string {
+	var seen [len(jsonTypeNames)]bool
+
+	for _, row := range rows {
+		if raw, ok := row[key]; ok {
+			typ, _ := JsonTypeOf(raw)
+			if typ == False {
+				typ = True
+			}
+			seen[typ] = true
+		}
+	}
+
+	names := make([]string, 0)
+
+	for t, ok := range seen {
+		switch {
+		case !ok:
+		case JsonType(t) == True:
+			names = append(names, "boolean")
+		default:
+			names = append(names, JsonType(t).String())
+		}
+	}
+
+	if len(names) == 0 {
+		return "missing"
+	}
+
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,761 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A TOML table that remembers key order and how it came to exist
+type tomlTable struct {
+	keys     []string
+	vals     map[string]interface{}
+	explicit bool // opened by a [header]
+	dotted   bool // created by a dotted key
+	inline   bool // an inline {table}, closed to further edits
+}
+
+// An array of values; aot marks an array of tables built from [[headers]]
+type tomlArray struct {
+	items []interface{}
+	aot   bool
+}
+
+// Local and offset dates/times have no JSON type and are carried through as strings
+type tomlDatetime string
+
+var (
+	tomlBareKey  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	tomlDecimal  = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlHex      = regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`)
+	tomlOctal    = regexp.MustCompile(`^0o[0-7](_?[0-7])*$`)
+	tomlBinary   = regexp.MustCompile(`^0b[01](_?[01])*$`)
+	tomlFloat    = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)((\.[0-9](_?[0-9])*)([eE][+-]?[0-9](_?[0-9])*)?|[eE][+-]?[0-9](_?[0-9])*)$`)
+	tomlDate     = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	tomlTime     = regexp.MustCompile(`^[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?$`)
+	tomlDateTime = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}[Tt ][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})?$`)
+)
+
+// Walks a TOML document, building a tree of tomlTables rooted at root
+type tomlParser struct {
+	data []byte
+	pos  int
+	line int
+	root *tomlTable
+	cur  *tomlTable
+}
+
+func newTomlTable() *tomlTable {
+	return &tomlTable{vals: make(map[string]interface{})}
+}
+
+// Converts a TOML document into raw JSON, mapping tables to objects and arrays to arrays
+func TomlToJson(data []byte) ([]byte, error) {
+	root, err := parseToml(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	writeTomlJson(&out, root)
+	return out.Bytes(), nil
+}
+
+// Generates a populated JsonDescription from a TOML document
+func DescribeToml(data []byte) (*JsonDescription, error) {
+	js, err := TomlToJson(data)
+
+	if err != nil {
+		return NewJsonDescription(), err
+	}
+
+	return Describe(js)
+}
+
+// UnmarshalTomlObject converts a TOML document to a new RawObject; a TOML document is always a table
+func UnmarshalTomlObject(in []byte) (*RawObject, error) {
+	js, err := TomlToJson(in)
+
+	if err != nil {
+		return new(RawObject), err
+	}
+
+	return UnmarshalObject(js)
+}
+
+func parseToml(data []byte) (*tomlTable, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("toml document is not valid utf-8")
+	}
+
+	p := &tomlParser{data: data, line: 1, root: newTomlTable()}
+	p.cur = p.root
+
+	for {
+		p.skipBlank(true)
+
+		if p.eof() {
+			return p.root, nil
+		}
+
+		var err error
+
+		if bytes.HasPrefix(p.data[p.pos:], []byte("[[")) {
+			err = p.arrayTableHeader()
+		} else if p.peek() == '[' {
+			err = p.tableHeader()
+		} else {
+			err = p.keyValue(p.cur)
+		}
+
+		if err == nil {
+			err = p.endOfLine()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid toml at line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+// Skips spaces, tabs, and comments, and newlines as well if multiline is set
+func (p *tomlParser) skipBlank(multiline bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		case multiline && c == '\n':
+			p.pos++
+			p.line++
+		case multiline && c == '\r' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '\n':
+			p.pos += 2
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+// Requires that nothing but whitespace or a comment follows on the current line
+func (p *tomlParser) endOfLine() error {
+	p.skipBlank(false)
+
+	switch {
+	case p.eof():
+		return nil
+	case p.peek() == '\n':
+		return nil
+	case p.peek() == '\r' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '\n':
+		return nil
+	}
+
+	return p.errorf("unexpected %q after value", p.peek())
+}
+
+func (p *tomlParser) expect(c byte) error {
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected %q, found end of input", c)
+		}
+		return p.errorf("expected %q, found %q", c, p.peek())
+	}
+
+	p.pos++
+	return nil
+}
+
+// Parses a possibly dotted key into its parts
+func (p *tomlParser) key() ([]string, error) {
+	var parts []string
+
+	for {
+		p.skipBlank(false)
+
+		var (
+			part string
+			err  error
+		)
+
+		switch p.peek() {
+		case '"':
+			part, err = p.basicString()
+		case '\'':
+			part, err = p.literalString()
+		default:
+			start := p.pos
+			for !p.eof() && tomlBareKey.Match(p.data[p.pos:p.pos+1]) {
+				p.pos++
+			}
+			part = string(p.data[start:p.pos])
+			if part == "" {
+				return nil, p.errorf("expected a key")
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, part)
+		p.skipBlank(false)
+
+		if p.peek() != '.' {
+			return parts, nil
+		}
+
+		p.pos++
+	}
+}
+
+// Descends through the intermediate parts of a dotted key, creating tables as needed
+func (p *tomlParser) descend(t *tomlTable, parts []string, dotted bool) (*tomlTable, error) {
+	for _, part := range parts {
+		switch next := t.vals[part].(type) {
+		case nil:
+			child := newTomlTable()
+			child.dotted = dotted
+			t.set(part, child)
+			t = child
+		case *tomlTable:
+			if next.inline || (dotted && next.explicit) {
+				return nil, p.errorf("cannot extend table %q", part)
+			}
+			t = next
+		case *tomlArray:
+			if !next.aot {
+				return nil, p.errorf("cannot extend static array %q", part)
+			}
+			t = next.items[len(next.items)-1].(*tomlTable)
+		default:
+			return nil, p.errorf("key %q is already defined as a value", part)
+		}
+	}
+
+	return t, nil
+}
+
+// Handles a [table] header
+func (p *tomlParser) tableHeader() error {
+	p.pos++
+
+	parts, err := p.key()
+
+	if err != nil {
+		return err
+	}
+
+	if err = p.expect(']'); err != nil {
+		return err
+	}
+
+	parent, err := p.descend(p.root, parts[:len(parts)-1], false)
+
+	if err != nil {
+		return err
+	}
+
+	last := parts[len(parts)-1]
+
+	switch existing := parent.vals[last].(type) {
+	case nil:
+		t := newTomlTable()
+		t.explicit = true
+		parent.set(last, t)
+		p.cur = t
+	case *tomlTable:
+		if existing.explicit || existing.dotted || existing.inline {
+			return p.errorf("table %q is already defined", strings.Join(parts, "."))
+		}
+		existing.explicit = true
+		p.cur = existing
+	default:
+		return p.errorf("key %q is already defined", strings.Join(parts, "."))
+	}
+
+	return nil
+}
+
+// Handles an [[array of tables]] header
+func (p *tomlParser) arrayTableHeader() error {
+	p.pos += 2
+
+	parts, err := p.key()
+
+	if err != nil {
+		return err
+	}
+
+	if err = p.expect(']'); err != nil {
+		return err
+	}
+
+	if err = p.expect(']'); err != nil {
+		return err
+	}
+
+	parent, err := p.descend(p.root, parts[:len(parts)-1], false)
+
+	if err != nil {
+		return err
+	}
+
+	last := parts[len(parts)-1]
+	t := newTomlTable()
+	t.explicit = true
+
+	switch existing := parent.vals[last].(type) {
+	case nil:
+		parent.set(last, &tomlArray{items: []interface{}{t}, aot: true})
+	case *tomlArray:
+		if !existing.aot {
+			return p.errorf("cannot append to static array %q", strings.Join(parts, "."))
+		}
+		existing.items = append(existing.items, t)
+	default:
+		return p.errorf("key %q is already defined", strings.Join(parts, "."))
+	}
+
+	p.cur = t
+	return nil
+}
+
+// Handles key = value within table t
+func (p *tomlParser) keyValue(t *tomlTable) error {
+	parts, err := p.key()
+
+	if err != nil {
+		return err
+	}
+
+	if err = p.expect('='); err != nil {
+		return err
+	}
+
+	p.skipBlank(false)
+	val, err := p.value()
+
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(t, parts[:len(parts)-1], true)
+
+	if err != nil {
+		return err
+	}
+
+	last := parts[len(parts)-1]
+
+	if _, exists := parent.vals[last]; exists {
+		return p.errorf("key %q is already defined", strings.Join(parts, "."))
+	}
+
+	parent.set(last, val)
+	return nil
+}
+
+func (t *tomlTable) set(k string, v interface{}) {
+	t.keys = append(t.keys, k)
+	t.vals[k] = v
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	switch c := p.peek(); c {
+	case '"':
+		if bytes.HasPrefix(p.data[p.pos:], []byte(`"""`)) {
+			return p.multilineString('"')
+		}
+		return p.basicString()
+	case '\'':
+		if bytes.HasPrefix(p.data[p.pos:], []byte(`'''`)) {
+			return p.multilineString('\'')
+		}
+		return p.literalString()
+	case '[':
+		return p.array()
+	case '{':
+		return p.inlineTable()
+	case 0:
+		return nil, p.errorf("expected a value")
+	}
+
+	return p.scalar()
+}
+
+// Parses booleans, numbers, and dates, which are delimited by whitespace or punctuation
+func (p *tomlParser) scalar() (interface{}, error) {
+	start := p.pos
+
+	for !p.eof() && strings.IndexByte("+-_.:0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", p.peek()) >= 0 {
+		p.pos++
+	}
+
+	tok := string(p.data[start:p.pos])
+
+	// A local date may be followed by a space and a time
+	if tomlDate.MatchString(tok) && len(p.data) > p.pos+3 && p.data[p.pos] == ' ' && p.data[p.pos+3] == ':' {
+		end := p.pos + 1
+		for end < len(p.data) && strings.IndexByte("+-.:0123456789Zz", p.data[end]) >= 0 {
+			end++
+		}
+		if tomlDateTime.MatchString(string(p.data[start:end])) {
+			p.pos = end
+			tok = string(p.data[start:end])
+		}
+	}
+
+	switch {
+	case tok == "true":
+		return true, nil
+	case tok == "false":
+		return false, nil
+	case tok == "inf" || tok == "+inf" || tok == "-inf" || tok == "nan" || tok == "+nan" || tok == "-nan":
+		return nil, p.errorf("%s has no json equivalent", tok)
+	case tomlDecimal.MatchString(tok):
+		return p.integer(strings.ReplaceAll(tok, "_", ""), 10)
+	case tomlHex.MatchString(tok):
+		return p.integer(strings.ReplaceAll(tok[2:], "_", ""), 16)
+	case tomlOctal.MatchString(tok):
+		return p.integer(strings.ReplaceAll(tok[2:], "_", ""), 8)
+	case tomlBinary.MatchString(tok):
+		return p.integer(strings.ReplaceAll(tok[2:], "_", ""), 2)
+	case tomlFloat.MatchString(tok):
+		f, err := strconv.ParseFloat(strings.ReplaceAll(tok, "_", ""), 64)
+		if err != nil || math.IsInf(f, 0) {
+			return nil, p.errorf("float %s is out of range", tok)
+		}
+		return f, nil
+	case tomlDate.MatchString(tok), tomlTime.MatchString(tok), tomlDateTime.MatchString(tok):
+		return tomlDatetime(tok), nil
+	case tok == "":
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+
+	return nil, p.errorf("invalid value %q", tok)
+}
+
+func (p *tomlParser) integer(digits string, base int) (interface{}, error) {
+	i, err := strconv.ParseInt(digits, base, 64)
+
+	if err != nil {
+		return nil, p.errorf("integer %s is out of range", digits)
+	}
+
+	return i, nil
+}
+
+// Parses a single-line "basic" string with escapes
+func (p *tomlParser) basicString() (string, error) {
+	var sb strings.Builder
+
+	p.pos++
+
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.peek()
+
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			return "", p.errorf("control character %U in string", c)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// Parses a single-line 'literal' string, which has no escapes
+func (p *tomlParser) literalString() (string, error) {
+	p.pos++
+	start := p.pos
+
+	for !p.eof() && p.peek() != '\'' {
+		if c := p.peek(); c == '\n' || c < 0x20 && c != '\t' || c == 0x7f {
+			return "", p.errorf("unterminated literal string")
+		}
+		p.pos++
+	}
+
+	if p.eof() {
+		return "", p.errorf("unterminated literal string")
+	}
+
+	s := string(p.data[start:p.pos])
+	p.pos++
+	return s, nil
+}
+
+// Parses a multiline basic or multiline literal string, delimited by three quote characters
+func (p *tomlParser) multilineString(quote byte) (string, error) {
+	var (
+		sb    strings.Builder
+		delim = []byte{quote, quote, quote}
+	)
+
+	p.pos += 3
+
+	// A newline immediately following the opening delimiter is trimmed
+	if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	} else if bytes.HasPrefix(p.data[p.pos:], []byte("\r\n")) {
+		p.pos += 2
+		p.line++
+	}
+
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multiline string")
+		}
+
+		if bytes.HasPrefix(p.data[p.pos:], delim) {
+			// Up to two quotes may sit immediately before the closing delimiter
+			extra := 0
+			for extra < 2 && p.pos+3+extra < len(p.data) && p.data[p.pos+3+extra] == quote {
+				extra++
+			}
+			sb.Write(p.data[p.pos : p.pos+extra])
+			p.pos += 3 + extra
+			return sb.String(), nil
+		}
+
+		c := p.peek()
+
+		switch {
+		case c == '\\' && quote == '"':
+			if p.trimLineEnding() {
+				continue
+			}
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		case c == '\n':
+			sb.WriteByte(c)
+			p.pos++
+			p.line++
+		case c == '\r' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '\n':
+			sb.WriteByte('\n')
+			p.pos += 2
+			p.line++
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			return "", p.errorf("control character %U in string", c)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// Consumes a line-ending backslash and all whitespace after it, reporting whether it did
+func (p *tomlParser) trimLineEnding() bool {
+	i := p.pos + 1
+
+	for i < len(p.data) && (p.data[i] == ' ' || p.data[i] == '\t') {
+		i++
+	}
+
+	if i >= len(p.data) || (p.data[i] != '\n' && p.data[i] != '\r') {
+		return false
+	}
+
+	for i < len(p.data) && strings.IndexByte(" \t\r\n", p.data[i]) >= 0 {
+		if p.data[i] == '\n' {
+			p.line++
+		}
+		i++
+	}
+
+	p.pos = i
+	return true
+}
+
+// Decodes one backslash escape sequence into sb
+func (p *tomlParser) escape(sb *strings.Builder) error {
+	if p.pos+1 >= len(p.data) {
+		return p.errorf("unterminated escape sequence")
+	}
+
+	c := p.data[p.pos+1]
+	p.pos += 2
+
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.data) {
+			return p.errorf("truncated unicode escape")
+		}
+		cp, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(cp)) {
+			return p.errorf("invalid unicode escape \\%c%s", c, p.data[p.pos:p.pos+n])
+		}
+		sb.WriteRune(rune(cp))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+
+	return nil
+}
+
+// Parses an [array] of values, which may span lines and carry a trailing comma
+func (p *tomlParser) array() (interface{}, error) {
+	arr := &tomlArray{items: make([]interface{}, 0)}
+
+	p.pos++
+
+	for {
+		p.skipBlank(true)
+
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+
+		val, err := p.value()
+
+		if err != nil {
+			return nil, err
+		}
+
+		arr.items = append(arr.items, val)
+		p.skipBlank(true)
+
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+
+		if err = p.expect(']'); err != nil {
+			return nil, err
+		}
+
+		return arr, nil
+	}
+}
+
+// Parses an {inline = "table"} on a single line
+func (p *tomlParser) inlineTable() (interface{}, error) {
+	t := newTomlTable()
+
+	p.pos++
+	p.skipBlank(false)
+
+	if p.peek() == '}' {
+		p.pos++
+		t.inline = true
+		return t, nil
+	}
+
+	for {
+		if err := p.keyValue(t); err != nil {
+			return nil, err
+		}
+
+		p.skipBlank(false)
+
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+
+		t.inline = true
+		return t, nil
+	}
+}
+
+// Serializes a parsed TOML value as JSON, preserving document key order
+func writeTomlJson(out *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case *tomlTable:
+		out.WriteByte('{')
+		for i, k := range val.keys {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			enc, _ := json.Marshal(k)
+			out.Write(enc)
+			out.WriteByte(':')
+			writeTomlJson(out, val.vals[k])
+		}
+		out.WriteByte('}')
+	case *tomlArray:
+		out.WriteByte('[')
+		for i, item := range val.items {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			writeTomlJson(out, item)
+		}
+		out.WriteByte(']')
+	case string:
+		enc, _ := json.Marshal(val)
+		out.Write(enc)
+	case tomlDatetime:
+		enc, _ := json.Marshal(string(val))
+		out.Write(enc)
+	case int64:
+		out.WriteString(strconv.FormatInt(val, 10))
+	case float64:
+		out.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+	case bool:
+		out.WriteString(strconv.FormatBool(val))
+	}
+}
@@ -0,0 +1,200 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Paths whose type an endpoint's responses newly held, or stopped holding, as seen by a ProfilingTransport
+type ShapeChange struct {
+	Endpoint string
+	// path:type pairs, as "/user/email:string", that no earlier response had
+	Added []string
+	// path:type pairs that every earlier response had but this one lacked
+	Missing []string
+}
+
+// An http.RoundTripper that profiles the JSON responses it passes back, endpoint by endpoint, for noticing when an API quietly changes its payloads
+//
+// A ProfilingTransport is safe for concurrent use, as a transport must be.
+type ProfilingTransport struct {
+	next      http.RoundTripper
+	cfg       *config
+	mu        sync.Mutex
+	endpoints map[string]*endpointProfile
+}
+
+// What a ProfilingTransport has learned of one endpoint
+type endpointProfile struct {
+	agg       *Aggregator
+	responses uint
+	// How many responses had each path:type pair
+	paths map[string]uint
+}
+
+// Constructor for ProfilingTransport, sending requests through next, or http.DefaultTransport if nil
+//
+// Honors WithEndpointKey, WithShapeChange, WithMetrics, and WithMaxBytes, past which a response is passed back unprofiled.
+func NewProfilingTransport(next http.RoundTripper, opts ...Option) *ProfilingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &ProfilingTransport{next: next, cfg: newConfig(opts), endpoints: make(map[string]*endpointProfile)}
+}
+
+// Implements http.RoundTripper, profiling a JSON response's body as the caller reads it to the end
+func (t *ProfilingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+
+	if err != nil || resp.Body == nil || !isJsonBody(resp.Header) {
+		return resp, err
+	}
+
+	endpoint := t.cfg.endpointKey(req)
+
+	resp.Body = &teeBody{ReadCloser: resp.Body, max: t.cfg.maxBytes, done: func(data []byte) {
+		t.record(endpoint, data)
+	}}
+
+	return resp, nil
+}
+
+// Lists the endpoints profiled so far, sorted
+func (t *ProfilingTransport) Endpoints() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return sortedKeys(t.endpoints)
+}
+
+// A copy of the merged shape of every response profiled for endpoint; nil if there were none
+func (t *ProfilingTransport) Shape(endpoint string) *Shape {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.endpoints[endpoint]
+	if !ok {
+		return nil
+	}
+
+	s := NewShape()
+	s.merge(p.agg.Shape())
+	return s
+}
+
+// Merges a complete response body into its endpoint's profile, reporting new and missing paths
+func (t *ProfilingTransport) record(endpoint string, data []byte) {
+	paths, err := pathSet(data)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+
+	p, ok := t.endpoints[endpoint]
+	if !ok {
+		p = &endpointProfile{agg: NewAggregator(WithMetrics(t.cfg.metrics)), paths: make(map[string]uint)}
+		t.endpoints[endpoint] = p
+	}
+
+	change := &ShapeChange{Endpoint: endpoint}
+
+	if p.responses > 0 {
+		for path := range paths {
+			if p.paths[path] == 0 {
+				change.Added = append(change.Added, path)
+			}
+		}
+		for path, n := range p.paths {
+			if n == p.responses && !paths[path] {
+				change.Missing = append(change.Missing, path)
+			}
+		}
+	}
+
+	for path := range paths {
+		p.paths[path]++
+	}
+
+	p.responses++
+	p.agg.Add(data)
+
+	t.mu.Unlock()
+
+	if t.cfg.onShapeChange != nil && (len(change.Added) > 0 || len(change.Missing) > 0) {
+		sort.Strings(change.Added)
+		sort.Strings(change.Missing)
+		t.cfg.onShapeChange(change)
+	}
+}
+
+// Names the endpoint a request is for: by WithEndpointKey, or else its method, host, and path with ID-like segments replaced by {id}
+func (c *config) endpointKey(req *http.Request) string {
+	if c.endpointKeyFn != nil {
+		return c.endpointKeyFn(req)
+	}
+
+	segments := strings.Split(req.URL.Path, "/")
+
+	for i, seg := range segments {
+		if isIdSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return req.Method + " " + req.URL.Host + strings.Join(segments, "/")
+}
+
+// Reports whether a path segment looks like an identifier rather than a name: all digits, a UUID, or long hexadecimal
+func isIdSegment(seg string) bool {
+	if seg == "" {
+		return false
+	}
+
+	if uuidPattern.MatchString(seg) {
+		return true
+	}
+
+	var digits, hex bool = true, len(seg) >= 16
+
+	for _, c := range seg {
+		digits = digits && '0' <= c && c <= '9'
+		hex = hex && strings.ContainsRune("0123456789abcdefABCDEF", c)
+	}
+
+	return digits || hex
+}
+
+// Copies a response body as it is read, handing the copy to done on reaching the end unless it ran past max
+type teeBody struct {
+	io.ReadCloser
+	max      int
+	buf      bytes.Buffer
+	overflow bool
+	finished bool
+	done     func([]byte)
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	if !b.overflow {
+		b.buf.Write(p[:n])
+		b.overflow = b.max > 0 && b.buf.Len() > b.max
+		if b.overflow {
+			b.buf = bytes.Buffer{}
+		}
+	}
+
+	if err == io.EOF && !b.overflow && !b.finished {
+		b.finished = true
+		b.done(b.buf.Bytes())
+	}
+
+	return n, err
+}
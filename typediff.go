@@ -0,0 +1,92 @@
+package jsondescriber
+
+// One change between two Shapes to the types generated from them, as found by Shape.DiffTypes
+type TypeChange struct {
+	// The location, with array indices as wildcards; "" for the documents themselves
+	Path string
+	// One of "added", "removed", "retyped", "optional", "required", "nullable", or "nonnullable"
+	Op string
+	// The kind of value before and after, e.g. "integer" and "number", or "object" and "map" for objects keyed by data; empty on the side where a member is missing, and for changes that keep the kind
+	Old string
+	New string
+}
+
+// Lists how the types generated from this Shape would differ from those generated from prev, a Shape built from earlier samples, for reviewing what re-running codegen on new samples would change
+//
+// The comparison is of the Shapes every generator works from, so it holds for GoTypes, TypeScriptTypes, ProtoMessages, and the schema generators alike, whatever their text looks like. A member is added or removed, its kind retyped, its presence made optional or required, or its values made nullable or nonnullable. A Shape saved with json.Marshal and read back serves as prev. Changes are listed parents before children, members in the order this Shape first saw them, with removed members after the rest. Honors WithPathStyle.
+func (s *Shape) DiffTypes(prev *Shape, opts ...Option) []TypeChange {
+	var (
+		cfg     = newConfig(opts)
+		changes = make([]TypeChange, 0)
+	)
+
+	diffTypes(prev, s, "", cfg.pathStyle, &changes)
+	return changes
+}
+
+// Names the kind of type generated for the values of a Shape, telling objects keyed by data apart as maps
+func (s *Shape) typeName() string {
+	kind := s.kind()
+
+	if kind == kindObject && s.keyedByValue() {
+		return "map"
+	}
+
+	return kind.String()
+}
+
+func diffTypes(old, new *Shape, path string, style PathStyle, changes *[]TypeChange) {
+	var (
+		oldName = old.typeName()
+		newName = new.typeName()
+	)
+
+	if oldName != newName {
+		*changes = append(*changes, TypeChange{Path: path, Op: "retyped", Old: oldName, New: newName})
+	}
+
+	if old.Nullable() != new.Nullable() {
+		op := "nonnullable"
+		if new.Nullable() {
+			op = "nullable"
+		}
+		*changes = append(*changes, TypeChange{Path: path, Op: op})
+	}
+
+	switch {
+	case oldName == "map" && newName == "map":
+		diffTypes(old.memberValues(), new.memberValues(), joinWildcard(style, path), style, changes)
+
+	case old.Types["object"] > 0 && new.Types["object"] > 0 && oldName != "map" && newName != "map":
+		for _, k := range new.Keys {
+			child := joinKey(style, path, k)
+			field := new.Fields[k]
+
+			prevField, ok := old.Fields[k]
+			if !ok {
+				*changes = append(*changes, TypeChange{Path: child, Op: "added", New: field.typeName()})
+				continue
+			}
+
+			if was, is := old.Required(k), new.Required(k); was != is {
+				op := "optional"
+				if is {
+					op = "required"
+				}
+				*changes = append(*changes, TypeChange{Path: child, Op: op})
+			}
+
+			diffTypes(prevField, field, child, style, changes)
+		}
+
+		for _, k := range old.Keys {
+			if _, ok := new.Fields[k]; !ok {
+				*changes = append(*changes, TypeChange{Path: joinKey(style, path, k), Op: "removed", Old: old.Fields[k].typeName()})
+			}
+		}
+	}
+
+	if old.Items != nil && new.Items != nil {
+		diffTypes(old.Items, new.Items, joinWildcard(style, path), style, changes)
+	}
+}
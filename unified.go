@@ -0,0 +1,110 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Renders the differences between two documents as a unified diff of their pretty-printed JSON, for attaching to tickets and reviewing like code
+//
+// Documents are indented two spaces per level with keys in document order, so reordered keys show as changes unless WithUnorderedArrays sorts them. Equal documents produce "". Honors WithDiffLabels, WithContextLines, WithIgnoreKeys, WithIgnorePaths, and WithUnorderedArrays.
+func UnifiedDiff(old, new []byte, opts ...Option) (string, error) {
+	var (
+		cfg   = newConfig(opts)
+		sides [2][]string
+	)
+
+	for i, data := range [][]byte{old, new} {
+		data = bytes.TrimSpace(data)
+
+		if _, err := TypeOf(data); err != nil {
+			return "", fmt.Errorf("%s: %w", cfg.diffLabels[i], err)
+		}
+
+		var out bytes.Buffer
+		json.Indent(&out, cfg.prune(data, ""), "", "  ")
+		sides[i] = strings.Split(out.String(), "\n")
+	}
+
+	var (
+		sb    strings.Builder
+		edits = myers(sides[0], sides[1])
+	)
+
+	for _, h := range unifiedHunks(edits, cfg.contextLines) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", cfg.diffLabels[0], cfg.diffLabels[1])
+		}
+
+		var (
+			first              = edits[h[0]]
+			oldCount, newCount int
+		)
+
+		for _, e := range edits[h[0]:h[1]] {
+			if e.Op != "inserted" {
+				oldCount++
+			}
+			if e.Op != "deleted" {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(first.OldIndex, oldCount), hunkRange(first.NewIndex, newCount))
+
+		for _, e := range edits[h[0]:h[1]] {
+			switch e.Op {
+			case "unchanged":
+				sb.WriteString(" " + sides[0][e.OldIndex] + "\n")
+			case "deleted":
+				sb.WriteString("-" + sides[0][e.OldIndex] + "\n")
+			case "inserted":
+				sb.WriteString("+" + sides[1][e.NewIndex] + "\n")
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// Groups the changed lines of an edit script into hunks with up to context unchanged lines around each, as [start, end) ranges of edits; hunks that would overlap are merged
+func unifiedHunks(edits []ArrayEdit, context int) [][2]int {
+	hunks := make([][2]int, 0)
+
+	for i, e := range edits {
+		if e.Op == "unchanged" {
+			continue
+		}
+
+		start, end := i-context, i+1+context
+		if start < 0 {
+			start = 0
+		}
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		if last := len(hunks) - 1; last >= 0 && start <= hunks[last][1] {
+			hunks[last][1] = end
+			continue
+		}
+
+		hunks = append(hunks, [2]int{start, end})
+	}
+
+	return hunks
+}
+
+// Writes a hunk's line range, e.g. "3,4"; an empty range names the line before it, as diff does
+func hunkRange(index, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", index)
+	case 1:
+		return fmt.Sprintf("%d", index+1)
+	}
+
+	return fmt.Sprintf("%d,%d", index+1, count)
+}
@@ -0,0 +1,181 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Categorizes a string that is accepted as JSON but may break a system downstream
+type StringIssueKind string
+
+const (
+	// Bytes that are not UTF-8, which decoders replace with U+FFFD or reject
+	StringInvalidUTF8 StringIssueKind = "invalid-utf8"
+	// A \u escape for half of a surrogate pair without its other half, which cannot be encoded as UTF-8
+	StringLoneSurrogate StringIssueKind = "lone-surrogate"
+	// The NUL character, which ends C strings early
+	StringNul StringIssueKind = "nul"
+	// A control character other than tab, newline, or carriage return, such as the escape that starts a terminal sequence
+	StringControl StringIssueKind = "control"
+	// A bidirectional formatting character, which can make text display in a different order from how it reads
+	StringBidi StringIssueKind = "bidi"
+	// A Unicode noncharacter, such as U+FFFE, which is reserved for internal use and should not be interchanged
+	StringNoncharacter StringIssueKind = "noncharacter"
+)
+
+// Reports a problem found by CheckStrings in a string, located by the JSON Pointer of its value, or of its member when Key is set
+type StringIssue struct {
+	Path string
+	// Whether the problem is in an object key rather than a string value
+	Key bool
+	// Byte offset in the document of the offending byte or escape
+	Offset int64
+	Kind   StringIssueKind
+}
+
+// Walks every string and key in a document and reports invalid UTF-8, lone surrogates, and characters written raw or escaped that commonly break or mislead systems downstream, which json.Valid lets through
+//
+// Results are in document order.
+func CheckStrings(data []byte) ([]StringIssue, error) {
+	var (
+		issues = make([]StringIssue, 0)
+		dec    = json.NewDecoder(bytes.NewReader(data))
+	)
+
+	if _, err := TypeOf(bytes.TrimSpace(data)); err != nil {
+		return issues, err
+	}
+
+	dec.UseNumber()
+
+	err := checkStrings(dec, data, "", &issues)
+	return issues, err
+}
+
+// Consumes one value from dec, auditing the strings within it
+func checkStrings(dec *json.Decoder, data []byte, ptr string, issues *[]StringIssue) error {
+	off := dec.InputOffset()
+	tok, err := dec.Token()
+
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			off = dec.InputOffset()
+
+			tok, err = dec.Token()
+			if err != nil {
+				return err
+			}
+
+			member := joinKey(PointerPath, ptr, tok.(string))
+			auditString(data, off, dec.InputOffset(), member, true, issues)
+
+			if err = checkStrings(dec, data, member, issues); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token()
+
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err = checkStrings(dec, data, joinIndex(PointerPath, ptr, i), issues); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token()
+
+	default:
+		if _, ok := tok.(string); ok {
+			auditString(data, off, dec.InputOffset(), ptr, false, issues)
+		}
+	}
+
+	return err
+}
+
+// Audits the string literal ending at end, found by skipping from off to its opening quote
+func auditString(data []byte, off, end int64, ptr string, key bool, issues *[]StringIssue) {
+	// The decoder stops just past the previous token, so skip the separator to reach the quote
+	for off < end && data[off] != '"' {
+		off++
+	}
+
+	report := func(at int64, kind StringIssueKind) {
+		*issues = append(*issues, StringIssue{Path: ptr, Key: key, Offset: at, Kind: kind})
+	}
+
+	for i := off + 1; i < end-1; {
+		if data[i] == '\\' {
+			r, size := unescapeRune(data[i : end-1])
+			if utf16.IsSurrogate(r) {
+				report(i, StringLoneSurrogate)
+			} else if kind, ok := runeIssue(r); ok {
+				report(i, kind)
+			}
+			i += int64(size)
+			continue
+		}
+
+		r, size := utf8.DecodeRune(data[i : end-1])
+		if r == utf8.RuneError && size == 1 {
+			report(i, StringInvalidUTF8)
+		} else if kind, ok := runeIssue(r); ok {
+			report(i, kind)
+		}
+		i += int64(size)
+	}
+}
+
+// Decodes the escape at the start of lit, joining a surrogate pair; a lone surrogate is returned as itself
+func unescapeRune(lit []byte) (rune, int) {
+	if lit[1] != 'u' {
+		return simpleEscapes[lit[1]], 2
+	}
+
+	r := hexRune(lit[2:6])
+
+	if utf16.IsSurrogate(r) && len(lit) >= 12 && lit[6] == '\\' && lit[7] == 'u' {
+		if pair := utf16.DecodeRune(r, hexRune(lit[8:12])); pair != unicode.ReplacementChar {
+			return pair, 12
+		}
+	}
+
+	return r, 6
+}
+
+// The characters that JSON's single-character escapes stand for
+var simpleEscapes = map[byte]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// Reads four hex digits, which the scanner has already checked
+func hexRune(hex []byte) rune {
+	n, _ := strconv.ParseUint(string(hex), 16, 32)
+	return rune(n)
+}
+
+// Reports what, if anything, is suspicious about a valid character
+func runeIssue(r rune) (StringIssueKind, bool) {
+	switch {
+	case r == 0:
+		return StringNul, true
+	case r == '\t' || r == '\n' || r == '\r':
+		return "", false
+	case unicode.IsControl(r):
+		return StringControl, true
+	case unicode.Is(unicode.Bidi_Control, r):
+		return StringBidi, true
+	case r >= 0xfdd0 && r <= 0xfdef, r&0xfffe == 0xfffe:
+		return StringNoncharacter, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,175 @@
+package jsondescriber
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Converts raw JSON into a YAML document in block style, keeping members in document order
+//
+// Strings are written plain when YAML would read them back as the same string, and double-quoted otherwise; numbers keep their digits, with any exponent spelled so that YAML 1.1 and 1.2 both read a float. Empty objects and arrays are written as {} and [].
+func JsonToYaml(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	if _, err := TypeOf(data); err != nil {
+		return nil, err
+	}
+
+	if err := encodeYaml(&out, bytes.TrimSpace(data), 0); err != nil {
+		return nil, err
+	}
+
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// Writes raw as a YAML node whose first line begins where out stands, and whose further lines are indented by indent spaces
+func encodeYaml(out *bytes.Buffer, raw json.RawMessage, indent int) error {
+	var (
+		typ, _ = TypeOf(raw)
+		pad    = "\n" + strings.Repeat(" ", indent)
+	)
+
+	switch *typ {
+	case "object":
+		members, err := orderedMembers(raw)
+		if err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			out.WriteString("{}")
+			return nil
+		}
+		for i, m := range members {
+			if i > 0 {
+				out.WriteString(pad)
+			}
+			out.WriteString(yamlString(m.Key) + ":")
+
+			// A sequence in a mapping may sit at the mapping's own indentation
+			switch yamlBlock(m.Value) {
+			case "object":
+				out.WriteString(pad + "  ")
+				err = encodeYaml(out, m.Value, indent+2)
+			case "array":
+				out.WriteString(pad)
+				err = encodeYaml(out, m.Value, indent)
+			default:
+				out.WriteByte(' ')
+				err = encodeYaml(out, m.Value, indent+2)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr := make(RawArray, 0)
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return err
+		}
+		if len(arr) == 0 {
+			out.WriteString("[]")
+			return nil
+		}
+		// Elements share the line of their dash, so a sequence of mappings or sequences is written compactly
+		for i := range arr {
+			if i > 0 {
+				out.WriteString(pad)
+			}
+			out.WriteString("- ")
+			if err := encodeYaml(out, arr[i], indent+2); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		out.WriteString(yamlString(s))
+
+	case "number":
+		out.WriteString(yamlNumber(string(raw)))
+
+	default:
+		out.Write(raw)
+	}
+
+	return nil
+}
+
+// Spells an exponent as YAML 1.1 requires for a float, with a point in the mantissa and a sign on the exponent, which YAML 1.2 reads too
+func yamlNumber(num string) string {
+	e := strings.IndexAny(num, "eE")
+
+	if e < 0 {
+		return num
+	}
+
+	mantissa, exp := num[:e], num[e+1:]
+
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+
+	if exp[0] != '+' && exp[0] != '-' {
+		exp = "+" + exp
+	}
+
+	return mantissa + "e" + exp
+}
+
+// Names the kind of block collection raw is written as, or "" for a scalar, which includes empty objects and arrays
+func yamlBlock(raw json.RawMessage) string {
+	var (
+		trimmed = bytes.TrimSpace(raw)
+		typ, _  = TypeOf(trimmed)
+	)
+
+	if *typ != "object" && *typ != "array" || len(bytes.TrimSpace(trimmed[1:len(trimmed)-1])) == 0 {
+		return ""
+	}
+
+	return *typ
+}
+
+// Scalars that YAML 1.1 or 1.2 would read as something other than a string
+var yamlReserved = map[string]bool{
+	"": true, "~": true, "null": true, "true": true, "false": true,
+	"yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+}
+
+// Leaves s plain where YAML reads it back as the same string, and double-quotes it otherwise; every JSON string escape is also a YAML one
+func yamlString(s string) string {
+	plain := !yamlReserved[strings.ToLower(s)] &&
+		!strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` \t.+0123456789") &&
+		!strings.HasSuffix(s, " ") && !strings.HasSuffix(s, ":") &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #")
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		plain = false
+	}
+
+	for _, c := range s {
+		if !unicode.IsPrint(c) {
+			plain = false
+		}
+	}
+
+	if plain {
+		return s
+	}
+
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}